@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	_ "rest-songs/docs"
 	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/auth"
 	"rest-songs/internal/app/config"
+	"rest-songs/internal/app/enrichment"
+	"rest-songs/internal/app/health"
 	httpHandler "rest-songs/internal/app/http"
+	"rest-songs/internal/app/musicinfo"
+	"rest-songs/internal/app/ratelimit"
 	"rest-songs/internal/app/repository/database"
 	"rest-songs/internal/app/repository/postgresql"
+	"rest-songs/internal/app/trash"
+	"rest-songs/migrations"
 )
 
 // @title Songs API
@@ -20,6 +34,10 @@ import (
 // @host localhost:8080
 // @basePath /
 // @schemes http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description API key or JWT as "Bearer <credentials>"
 
 func main() {
 
@@ -35,35 +53,200 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create a new connection pool to database
+	// Create a new connection pool to database, it is closed last on shutdown
 	pool, err := database.NewPool(cfg.DbUrl)
 	if err != nil {
 		log.Errorf("Ошибка при создании соединения к базе данных: %v", err)
 		os.Exit(1)
 	}
-	defer pool.Close()
 
 	// Create a new Database with connection pool
 	db := database.NewDatabase(pool)
 
-	// Create a new repo with Database and logger
-	repo := postgresql.New(*db, log)
+	// Create a new repo with Database, query timeouts and logger
+	repo := postgresql.New(*db, postgresql.Timeouts{
+		Read:  cfg.DbReadTimeout,
+		Write: cfg.DbWriteTimeout,
+		Bulk:  cfg.DbBulkTimeout,
+	}, log)
 
 	// Create a new service
 	songService := api.New(repo, log)
 
+	// Create music info client for external API
+	musicInfo := musicinfo.NewClient(musicinfo.Config{
+		BaseURL:          cfg.ExternalAPI,
+		Timeout:          cfg.ExternalAPITimeout,
+		MaxRetries:       cfg.ExternalAPIMaxRetries,
+		RetryBackoff:     cfg.ExternalAPIRetryBackoff,
+		MaxRetryBackoff:  cfg.ExternalAPIMaxRetryBackoff,
+		BreakerThreshold: cfg.ExternalAPIBreakerThreshold,
+		BreakerCooldown:  cfg.ExternalAPIBreakerCooldown,
+	}, log)
+
+	// Start background enrichment of pending songs
+	worker := enrichment.New(repo, musicInfo, enrichment.Config{
+		Workers:         cfg.EnrichmentWorkers,
+		PollInterval:    cfg.EnrichmentPollInterval,
+		Lease:           cfg.EnrichmentLease,
+		MaxAttempts:     cfg.EnrichmentMaxAttempts,
+		RetryBackoff:    cfg.EnrichmentRetryBackoff,
+		MaxRetryBackoff: cfg.EnrichmentMaxRetryBackoff,
+	}, log)
+
+	// Background workers run until shutdown of server
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	runWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workersCtx)
+		}()
+	}
+	runWorker(worker.Run)
+
+	// Start background purge of songs which stay in trash longer than retention period
+	purger := trash.New(repo, trash.Config{
+		Retention: cfg.TrashRetention,
+		Interval:  cfg.TrashPurgeInterval,
+	}, log)
+	runWorker(purger.Run)
+
+	// Create checker of service health, schema version is expected to match the newest embedded migration
+	expectedMigration, err := migrations.LatestVersion()
+	if err != nil {
+		log.Errorf("Ошибка при чтении миграций: %v", err)
+		os.Exit(1)
+	}
+	checker := health.New(repo, musicInfo, health.Config{
+		ExpectedMigration: expectedMigration,
+		Timeout:           cfg.HealthCheckTimeout,
+		MusicInfo:         cfg.HealthMusicInfo,
+		MusicInfoTTL:      cfg.HealthMusicInfoTTL,
+	}, log)
+
 	// Create Http handler
-	handler := httpHandler.New(songService, cfg.ExternalAPI, log)
+	handler := httpHandler.New(songService, musicInfo, checker, cfg.HttpBulkTimeout, log)
+
+	// Create JWT verifier with locally configured keys
+	verifier, err := auth.NewVerifier(auth.VerifierConfig{
+		HS256Secret: cfg.JWTSecret,
+		KeysFile:    cfg.JWTKeysFile,
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+	})
+	if err != nil {
+		log.Errorf("Ошибка при загрузке ключей JWT: %v", err)
+		os.Exit(1)
+	}
+
+	// Create authenticator of API keys and JWT
+	authenticator := auth.New(songService, verifier, auth.Config{
+		Enabled:           cfg.AuthEnabled,
+		BootstrapAdminKey: cfg.AuthBootstrapAdminKey,
+		Public:            []string{"/docs/swagger/", "/healthz", "/readyz"},
+	}, log)
+	if !cfg.AuthEnabled {
+		log.Warn("Аутентификация отключена, API доступен без ключа")
+	}
+
+	// Create rate limiter of clients, daily quotas are kept in database
+	limiter := ratelimit.New(repo, ratelimit.Config{
+		Enabled:  cfg.RateLimitEnabled,
+		Read:     ratelimit.Limit(cfg.RateLimitRead),
+		Write:    ratelimit.Limit(cfg.RateLimitWrite),
+		Upstream: ratelimit.Limit(cfg.RateLimitUpstream),
+		IP:       ratelimit.Limit(cfg.RateLimitIP),
+		Exempt:   []string{"/docs/swagger/", "/healthz", "/readyz"},
+	}, log)
+	runWorker(limiter.Run)
 
 	// Init Router
 	r := mux.NewRouter()
+	// Requests are limited by address before authentication, so that failed attempts are limited too
+	r.Use(limiter.IPMiddleware)
+	r.Use(authenticator.Middleware)
+	r.Use(limiter.Middleware)
 
 	handler.RegisterRoutes(r)
 
-	// Start HTTP server
-	if err = http.ListenAndServe(cfg.HttpPort, r); err != nil {
-		log.Fatalf("Не удалось запустить сервер: %v", err)
+	server := &http.Server{
+		Addr:              cfg.HttpPort,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.HttpReadHeaderTimeout,
+		ReadTimeout:       cfg.HttpReadTimeout,
+		WriteTimeout:      cfg.HttpWriteTimeout,
+		IdleTimeout:       cfg.HttpIdleTimeout,
+		MaxHeaderBytes:    cfg.HttpMaxHeaderBytes,
+	}
+
+	// Start HTTP server and wait for termination signal
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serverErr := make(chan error, 1)
+	listener, err := net.Listen("tcp", cfg.HttpPort)
+	if err != nil {
+		serverErr <- err
+	} else {
+		log.Infof("Сервер работает на порту: %s", cfg.HttpPort)
+		go func() {
+			if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+	}
+
+	exitCode := 0
+	select {
+	case <-signals.Done():
+		log.Infof("Получен сигнал завершения, остановка сервера")
+	case err = <-serverErr:
+		log.Errorf("Не удалось запустить сервер: %v", err)
+		exitCode = 1
+	}
+	stopSignals() // Second signal terminates process at once
+
+	// Report not ready first, so that load balancer stops sending new requests before server stops accepting them
+	checker.Shutdown()
+	if cfg.ShutdownDrainDelay > 0 {
+		log.Infof("Ожидание %s перед остановкой сервера", cfg.ShutdownDrainDelay)
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
+	shutdown(server, stopWorkers, &workers, pool.Close, cfg.ShutdownTimeout, log)
+	os.Exit(exitCode)
+}
+
+// shutdown stops application in order: server stops accepting connections and drains in-flight requests,
+// then background workers finish their current jobs, then database connection pool is closed.
+// The first two steps share timeout, requests still running after it are cut off
+func shutdown(server *http.Server, stopWorkers context.CancelFunc, workers *sync.WaitGroup, closePool func(),
+	timeout time.Duration, log *logrus.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Errorf("shutdown[main]: Не все запросы завершились вовремя: %v", err)
+		server.Close()
+	} else {
+		log.Infof("shutdown[main]: HTTP сервер остановлен")
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Infof("shutdown[main]: Фоновые обработчики остановлены")
+	case <-ctx.Done():
+		log.Warnf("shutdown[main]: Фоновые обработчики не остановились вовремя")
 	}
 
-	log.Infof("Сервер работает на порту: %s :", cfg.HttpPort)
+	closePool()
+	log.Infof("shutdown[main]: Соединения с базой данных закрыты")
 }
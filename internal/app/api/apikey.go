@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"rest-songs/internal/app/auth"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+var (
+	ErrEmptyKeyName = errors.New("api key name is empty")
+	ErrInvalidRole  = errors.New("invalid role")
+)
+
+// APIKeyService defines interface for issuing and checking API keys
+type APIKeyService interface {
+	IssueAPIKey(ctx context.Context, input models.APIKeyRequest) (models.IssuedAPIKey, error)
+	GetAPIKeys(ctx context.Context, page, pageSize int) (models.Page[models.APIKey], error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error)
+}
+
+// IssueAPIKey generates new API key with role, only hash of key is stored
+func (s *SongService) IssueAPIKey(ctx context.Context, input models.APIKeyRequest) (models.IssuedAPIKey, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.IssuedAPIKey{}, ErrEmptyKeyName
+	}
+	if !auth.ValidRole(input.Role) {
+		return models.IssuedAPIKey{}, ErrInvalidRole
+	}
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		s.logger.Errorf("IssueAPIKey[service]: Ошибка генерации ключа: %v", err)
+		return models.IssuedAPIKey{}, err
+	}
+
+	stored, err := s.repo.CreateAPIKey(ctx, name, prefix, input.Role, auth.HashAPIKey(key))
+	if err != nil {
+		return models.IssuedAPIKey{}, err
+	}
+	return models.IssuedAPIKey{APIKey: stored, Key: key}, nil
+}
+
+// GetAPIKeys retrieves page of API keys, newest first
+func (s *SongService) GetAPIKeys(ctx context.Context, page, pageSize int) (models.Page[models.APIKey], error) {
+	keys, err := s.repo.GetAPIKeys(ctx, page, pageSize)
+	if err != nil {
+		return models.Page[models.APIKey]{}, err
+	}
+
+	total, err := s.repo.CountAPIKeys(ctx)
+	if err != nil {
+		return models.Page[models.APIKey]{}, err
+	}
+
+	result := models.Page[models.APIKey]{Items: keys, Page: page, PageSize: pageSize}
+	result.SetTotal(total, false)
+	return result, nil
+}
+
+// RevokeAPIKey revokes API key by ID, requests with it are rejected from then on
+func (s *SongService) RevokeAPIKey(ctx context.Context, id int) error {
+	return s.repo.RevokeAPIKey(ctx, id)
+}
+
+// AuthenticateAPIKey returns principal of active API key, or auth.ErrInvalidCredentials for unknown key
+func (s *SongService) AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	stored, err := s.repo.GetAPIKeyByHash(ctx, auth.HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, postgresql.ErrAPIKeyNotFound) {
+			return auth.Principal{}, auth.ErrInvalidCredentials
+		}
+		return auth.Principal{}, err
+	}
+	return auth.Principal{Subject: stored.Name, Role: stored.Role, Method: auth.MethodAPIKey, KeyID: stored.ID}, nil
+}
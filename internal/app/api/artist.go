@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+var ErrEmptyArtistName = errors.New("artist name is empty")
+
+// ArtistService defines interface for managing artists (groups) and their discography
+type ArtistService interface {
+	GetArtists(ctx context.Context, page, pageSize int) ([]models.Artist, error)
+	GetArtistById(ctx context.Context, id int) (models.Artist, error)
+	CreateArtist(ctx context.Context, name string) (models.Artist, error)
+	UpdateArtist(ctx context.Context, id int, name string) (models.Artist, error)
+	DeleteArtist(ctx context.Context, id int) error
+	GetArtistSongs(ctx context.Context, id int) ([]models.Song, error)
+}
+
+// GetArtists retrieves list of artists from repository with pagination
+func (s *SongService) GetArtists(ctx context.Context, page, pageSize int) ([]models.Artist, error) {
+	return s.repo.GetArtists(ctx, page, pageSize)
+}
+
+// GetArtistById retrieves artist by ID using repository
+func (s *SongService) GetArtistById(ctx context.Context, id int) (models.Artist, error) {
+	return s.repo.GetArtistById(ctx, id)
+}
+
+// CreateArtist creates new artist with trimmed name using repository
+func (s *SongService) CreateArtist(ctx context.Context, name string) (models.Artist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Artist{}, ErrEmptyArtistName
+	}
+	return s.repo.CreateArtist(ctx, name)
+}
+
+// UpdateArtist renames artist using repository, name of every artist song is updated too
+func (s *SongService) UpdateArtist(ctx context.Context, id int, name string) (models.Artist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Artist{}, ErrEmptyArtistName
+	}
+	return s.repo.UpdateArtist(ctx, id, name)
+}
+
+// DeleteArtist deletes artist without songs by ID using repository
+func (s *SongService) DeleteArtist(ctx context.Context, id int) error {
+	return s.repo.DeleteArtist(ctx, id)
+}
+
+// GetArtistSongs retrieves songs of artist in chronological order using repository
+func (s *SongService) GetArtistSongs(ctx context.Context, id int) ([]models.Song, error) {
+	return s.repo.GetArtistSongs(ctx, id)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"rest-songs/internal/app/chordpro"
+	"rest-songs/internal/app/models"
+)
+
+var (
+	ErrInvalidNotation  = errors.New("invalid chord notation")
+	ErrInvalidTranspose = errors.New("invalid transpose")
+)
+
+// ChordService defines interface for ChordPro chord sheets of songs
+type ChordService interface {
+	GetSongChords(ctx context.Context, id int, options models.ChordOptions) (models.ChordSheet, error)
+	SetSongChords(ctx context.Context, id int, document string) (models.ChordSheet, error)
+	DeleteSongChords(ctx context.Context, id int) error
+}
+
+// GetSongChords retrieves chord sheet of song transposed and rendered in notation from options
+func (s *SongService) GetSongChords(ctx context.Context, id int, options models.ChordOptions) (models.ChordSheet, error) {
+	s.logger.Infof("GetSongChords[service]: Получение аккордов песни ID: %d, транспонирование: %d, нотация: %s",
+		id, options.Transpose, options.Notation)
+
+	switch options.Notation {
+	case "", models.NotationLatin, models.NotationGerman, models.NotationNashville:
+	default:
+		return models.ChordSheet{}, ErrInvalidNotation
+	}
+	if options.Transpose < -11 || options.Transpose > 11 {
+		return models.ChordSheet{}, ErrInvalidTranspose
+	}
+
+	document, err := s.repo.GetChordSheet(ctx, id)
+	if err != nil {
+		return models.ChordSheet{}, err
+	}
+
+	sheet, err := chordpro.Parse(document)
+	if err != nil {
+		s.logger.Errorf("GetSongChords[service]: Ошибка разбора сохраненных аккордов песни ID %d: %v", id, err)
+		return models.ChordSheet{}, err
+	}
+	sheet.SongID = id
+
+	return chordpro.Render(sheet, options)
+}
+
+// SetSongChords validates ChordPro document and sets it as chord sheet of song
+// Malformed document is rejected with chordpro.SyntaxErrors listing all errors with their lines
+func (s *SongService) SetSongChords(ctx context.Context, id int, document string) (models.ChordSheet, error) {
+	s.logger.Infof("SetSongChords[service]: Сохранение аккордов песни ID: %d", id)
+
+	sheet, err := chordpro.Parse(document)
+	if err != nil {
+		s.logger.Warnf("SetSongChords[service]: Неправильный документ ChordPro песни ID %d: %v", id, err)
+		return models.ChordSheet{}, err
+	}
+	sheet.SongID = id
+
+	if err = s.repo.SaveChordSheet(ctx, id, document); err != nil {
+		return models.ChordSheet{}, err
+	}
+	return chordpro.Render(sheet, models.ChordOptions{})
+}
+
+// DeleteSongChords removes chord sheet of song
+func (s *SongService) DeleteSongChords(ctx context.Context, id int) error {
+	return s.repo.DeleteChordSheet(ctx, id)
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+
+	"rest-songs/internal/app/models"
+)
+
+// ExportService defines interface for export of whole library
+type ExportService interface {
+	ExportSongs(ctx context.Context, filter models.SongFilters, sort models.SongSort, columns []string, fn func(models.Song) error) error
+}
+
+// ExportSongs passes every song matching filter in given order to fn using repository
+// Only given columns of songs are read, all of them if columns is empty
+// It returns *FilterError if filter or sort is inconsistent, before any song is passed
+func (s *SongService) ExportSongs(ctx context.Context, filter models.SongFilters, sort models.SongSort, columns []string, fn func(models.Song) error) error {
+	if err := validateSongFilters(filter); err != nil {
+		s.logger.Warnf("ExportSongs[service]: Неправильный фильтр: %v", err)
+		return err
+	}
+	if err := validateSongSort(sort); err != nil {
+		s.logger.Warnf("ExportSongs[service]: Неправильная сортировка: %v", err)
+		return err
+	}
+
+	return s.repo.ExportSongs(ctx, filter, sort, columns, fn)
+}
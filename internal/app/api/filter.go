@@ -0,0 +1,65 @@
+package api
+
+import (
+	"rest-songs/internal/app/models"
+)
+
+// FilterError describes invalid combination of song filters
+// Reason is human readable and is returned to client as is
+type FilterError struct {
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return "invalid filter: " + e.Reason
+}
+
+// validateSongFilters checks that filter fields are consistent with each other
+func validateSongFilters(filter models.SongFilters) error {
+	switch filter.Match {
+	case "", models.MatchExact, models.MatchPrefix, models.MatchContains:
+	default:
+		return &FilterError{Reason: "match должен быть одним из: exact, prefix, contains"}
+	}
+
+	if filter.Match != "" && len(filter.Groups) == 0 && filter.Title == "" {
+		return &FilterError{Reason: "match используется только вместе с group или song"}
+	}
+
+	if !filter.ReleaseDate.IsZero() && (!filter.ReleaseFrom.IsZero() || !filter.ReleaseTo.IsZero()) {
+		return &FilterError{Reason: "release_date нельзя использовать вместе с release_from или release_to"}
+	}
+
+	if !filter.ReleaseFrom.IsZero() && !filter.ReleaseTo.IsZero() && filter.ReleaseFrom.After(filter.ReleaseTo) {
+		return &FilterError{Reason: "release_from не может быть позже release_to"}
+	}
+
+	return nil
+}
+
+// validateSongSort checks that songs are sorted by allowed columns, each used once, with known collation
+func validateSongSort(sort models.SongSort) error {
+	switch sort.Collation {
+	case "", models.CollationRussian, models.CollationEnglish:
+	default:
+		return &FilterError{Reason: "collation должен быть одним из: ru, en"}
+	}
+
+	seen := make(map[string]bool, len(sort.Fields))
+	for _, field := range sort.Fields {
+		switch field.Column {
+		case models.SortByID, models.SortByGroup, models.SortByTitle,
+			models.SortByReleaseDate, models.SortByCreatedAt, models.SortByUpdatedAt:
+		default:
+			return &FilterError{Reason: "сортировка по " + field.Column + " не поддерживается, " +
+				"допустимы: id, group, song, release_date, created_at, updated_at"}
+		}
+
+		if seen[field.Column] {
+			return &FilterError{Reason: "поле " + field.Column + " указано в сортировке несколько раз"}
+		}
+		seen[field.Column] = true
+	}
+
+	return nil
+}
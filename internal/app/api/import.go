@@ -0,0 +1,284 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-songs/internal/app/models"
+)
+
+// importBatchSize is number of valid rows copied into database at once
+const importBatchSize = 500
+
+// ImportError describes import file which can't be read at all, e.g. CSV without required columns
+type ImportError struct {
+	Reason string
+}
+
+func (e *ImportError) Error() string {
+	return "invalid import: " + e.Reason
+}
+
+// rowError describes malformed row of import file, rows after it are still read
+type rowError struct {
+	reason string
+}
+
+func (e *rowError) Error() string {
+	return e.reason
+}
+
+// ImportService defines interface for bulk import of songs
+type ImportService interface {
+	ImportSongs(ctx context.Context, body io.Reader, options models.ImportOptions) (models.ImportReport, error)
+}
+
+// ImportSongs reads song records from CSV or NDJSON body, validates each of them and loads valid ones
+// in batches within single transaction. Invalid rows are rejected and reported, they don't abort import.
+// In dry run transaction is rolled back, so nothing is changed
+func (s *SongService) ImportSongs(ctx context.Context, body io.Reader, options models.ImportOptions) (models.ImportReport, error) {
+	s.logger.Infof("ImportSongs[service]: Импорт песен: %+v", options)
+
+	if options.Mode == "" {
+		options.Mode = models.ImportInsert
+	}
+	if options.Mode != models.ImportInsert && options.Mode != models.ImportUpsert {
+		return models.ImportReport{}, &ImportError{Reason: "mode должен быть одним из: insert, upsert"}
+	}
+
+	reader, err := newImportReader(options.Format, body)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	importer, err := s.repo.BeginImport(ctx, options.Mode == models.ImportUpsert)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+	defer importer.Rollback(ctx)
+
+	report := models.ImportReport{Mode: options.Mode, DryRun: options.DryRun, Rows: []models.ImportRow{}}
+	batch := make([]models.Song, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created, updated, err := importer.Load(ctx, batch)
+		if err != nil {
+			return err
+		}
+		report.Created += created
+		report.Updated += updated
+		batch = batch[:0]
+		return nil
+	}
+
+	// Rows with the same group and title can't be upserted within one import, since result would depend on order
+	seen := make(map[string]int)
+	for {
+		line, record, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var song models.Song
+		if err == nil {
+			song, err = importedSong(record)
+		}
+		if err == nil && options.Mode == models.ImportUpsert {
+			key := strings.ToLower(song.Group) + "\x00" + song.Title
+			if first, ok := seen[key]; ok {
+				err = &rowError{reason: "повторяет строку " + strconv.Itoa(first)}
+			} else {
+				seen[key] = line
+			}
+		}
+
+		report.Total++
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			report.Rejected++
+			report.Rows = append(report.Rows, models.ImportRow{
+				Line: line, Status: models.ImportRejected, Group: record.Group, Song: record.Song, Reason: rowErr.reason,
+			})
+			continue
+		}
+		if err != nil {
+			s.logger.Errorf("ImportSongs[service]: Ошибка чтения строки %d: %v", line, err)
+			return models.ImportReport{}, err
+		}
+
+		report.Accepted++
+		report.Rows = append(report.Rows, models.ImportRow{
+			Line: line, Status: models.ImportAccepted, Group: song.Group, Song: song.Title,
+		})
+		batch = append(batch, song)
+		if len(batch) == importBatchSize {
+			if err = flush(); err != nil {
+				return models.ImportReport{}, err
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return models.ImportReport{}, err
+	}
+
+	if !options.DryRun {
+		if err = importer.Commit(ctx); err != nil {
+			s.logger.Errorf("ImportSongs[service]: Ошибка фиксации импорта: %v", err)
+			return models.ImportReport{}, err
+		}
+	}
+
+	s.logger.Infof("ImportSongs[service]: Импорт завершен, принято: %d, отклонено: %d, создано: %d, обновлено: %d",
+		report.Accepted, report.Rejected, report.Created, report.Updated)
+	return report, nil
+}
+
+// importedSong validates import record and converts it into song
+func importedSong(record models.ImportRecord) (models.Song, error) {
+	group := strings.TrimSpace(record.Group)
+	if group == "" {
+		return models.Song{}, &rowError{reason: "group не может быть пустым"}
+	}
+	if record.Song == "" {
+		return models.Song{}, &rowError{reason: "song не может быть пустым"}
+	}
+
+	releaseDate, err := time.Parse("02.01.2006", record.ReleaseDate)
+	if err != nil {
+		return models.Song{}, &rowError{reason: "неправильный формат даты в поле release_date, ожидается ДД.ММ.ГГГГ"}
+	}
+
+	return models.Song{
+		Group:       group,
+		Title:       record.Song,
+		ReleaseDate: &releaseDate,
+		Text:        record.Text,
+		Link:        record.Link,
+	}, nil
+}
+
+// importReader reads song records from import file one by one
+type importReader interface {
+	// next returns next record with number of line it starts on, and io.EOF after the last one
+	// Malformed record is reported with *rowError, and reading may continue after it
+	next() (int, models.ImportRecord, error)
+}
+
+// newImportReader creates reader of import file in given format
+func newImportReader(format string, body io.Reader) (importReader, error) {
+	switch format {
+	case models.FormatCSV:
+		return newCSVImportReader(body)
+	case models.FormatNDJSON:
+		return &ndjsonImportReader{reader: bufio.NewReader(body)}, nil
+	default:
+		return nil, &ImportError{Reason: "формат должен быть одним из: csv, ndjson"}
+	}
+}
+
+// csvImportReader reads CSV file with header row naming columns group, song, release_date, text and link
+// in any order. text and link columns may be omitted
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &ImportError{Reason: "не удалось прочитать заголовок CSV"}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF"))
+		switch name {
+		case "group", "song", "release_date", "text", "link":
+		default:
+			return nil, &ImportError{Reason: "неизвестная колонка CSV: " + name}
+		}
+		if _, ok := columns[name]; ok {
+			return nil, &ImportError{Reason: "колонка CSV повторяется: " + name}
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"group", "song", "release_date"} {
+		if _, ok := columns[name]; !ok {
+			return nil, &ImportError{Reason: "нет обязательной колонки CSV: " + name}
+		}
+	}
+
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) next() (int, models.ImportRecord, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.StartLine, models.ImportRecord{}, &rowError{reason: "неправильный формат CSV: " + parseErr.Err.Error()}
+		}
+		return 0, models.ImportRecord{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	if len(fields) != len(r.columns) {
+		return line, models.ImportRecord{}, &rowError{reason: "количество полей не совпадает с заголовком"}
+	}
+
+	return line, models.ImportRecord{
+		Group:       fields[r.columns["group"]],
+		Song:        fields[r.columns["song"]],
+		ReleaseDate: fields[r.columns["release_date"]],
+		Text:        r.field(fields, "text"),
+		Link:        r.field(fields, "link"),
+	}, nil
+}
+
+// field returns value of optional column, or empty string if there is no such column
+func (r *csvImportReader) field(fields []string, name string) string {
+	if i, ok := r.columns[name]; ok {
+		return fields[i]
+	}
+	return ""
+}
+
+// ndjsonImportReader reads file of JSON objects, one per line. Empty lines are skipped
+type ndjsonImportReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+func (r *ndjsonImportReader) next() (int, models.ImportRecord, error) {
+	for {
+		data, err := r.reader.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return 0, models.ImportRecord{}, err
+		}
+		r.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		var record models.ImportRecord
+		if err = json.Unmarshal(data, &record); err != nil {
+			return r.line, models.ImportRecord{}, &rowError{reason: "неправильный JSON"}
+		}
+		return r.line, record, nil
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-songs/internal/app/models"
+)
+
+var ErrPatchTestFailed = errors.New("patch test operation failed")
+
+// PatchError describes invalid patch document
+// Reason is human readable and is returned to client as is
+type PatchError struct {
+	Reason string
+}
+
+func (e *PatchError) Error() string {
+	return "invalid patch: " + e.Reason
+}
+
+// Editable song fields as named in JSON
+const (
+	fieldGroup       = "group"
+	fieldTitle       = "song"
+	fieldReleaseDate = "release_date"
+	fieldText        = "text"
+	fieldLink        = "link"
+)
+
+// readOnlyFields are song fields present in JSON which can't be patched
+var readOnlyFields = map[string]bool{
+	"id": true, "artist_id": true, "enrichment_status": true, "created_at": true, "updated_at": true,
+}
+
+// MergePatch converts JSON Merge Patch (RFC 7396) document, decoded into map, into SongPatch
+// null removes text or link (sets them empty), while group, song and release_date can't be removed
+func MergePatch(doc map[string]interface{}) (models.SongPatch, error) {
+	return songPatchFromFields(doc)
+}
+
+// JSONPatch applies JSON Patch (RFC 6902) operations to song and returns SongPatch of fields which changed
+// Failed test operation results in ErrPatchTestFailed
+func JSONPatch(song models.Song, operations []models.PatchOperation) (models.SongPatch, error) {
+	original := songFields(song)
+	doc := songFields(song)
+
+	for i, operation := range operations {
+		if err := applyOperation(doc, operation); err != nil {
+			var patchErr *PatchError
+			if errors.As(err, &patchErr) {
+				return models.SongPatch{}, &PatchError{Reason: "операция " + strconv.Itoa(i) + ": " + patchErr.Reason}
+			}
+			return models.SongPatch{}, err
+		}
+	}
+
+	changed := make(map[string]interface{})
+	for field, value := range doc {
+		if original[field] != value {
+			changed[field] = value
+		}
+	}
+	return songPatchFromFields(changed)
+}
+
+// songFields returns editable fields of song as they are represented in JSON document
+// Missing release date is represented as nil
+func songFields(song models.Song) map[string]interface{} {
+	fields := map[string]interface{}{
+		fieldGroup:       song.Group,
+		fieldTitle:       song.Title,
+		fieldReleaseDate: nil,
+		fieldText:        song.Text,
+		fieldLink:        song.Link,
+	}
+	if song.ReleaseDate != nil {
+		fields[fieldReleaseDate] = song.ReleaseDate.Format("02.01.2006")
+	}
+	return fields
+}
+
+// applyOperation applies single JSON Patch operation to flat document of song fields
+func applyOperation(doc map[string]interface{}, operation models.PatchOperation) error {
+	field, err := pointerField(operation.Path)
+	if err != nil {
+		return err
+	}
+
+	// Song fields are strings, so only string or null values are meaningful
+	if _, ok := operation.Value.(string); !ok && operation.Value != nil {
+		return &PatchError{Reason: "значение поля " + field + " должно быть строкой"}
+	}
+
+	switch operation.Op {
+	case "add", "replace":
+		doc[field] = operation.Value
+	case "remove":
+		doc[field] = nil
+	case "test":
+		if doc[field] != operation.Value {
+			return ErrPatchTestFailed
+		}
+	case "move", "copy":
+		from, err := pointerField(operation.From)
+		if err != nil {
+			return err
+		}
+		doc[field] = doc[from]
+		if operation.Op == "move" && from != field {
+			doc[from] = nil
+		}
+	default:
+		return &PatchError{Reason: "неизвестная операция " + operation.Op}
+	}
+	return nil
+}
+
+// pointerField converts JSON Pointer of top level song field (e.g. "/text") into field name
+func pointerField(pointer string) (string, error) {
+	field := strings.TrimPrefix(pointer, "/")
+	if field == pointer || strings.Contains(field, "/") {
+		return "", &PatchError{Reason: "неправильный путь " + pointer}
+	}
+	if readOnlyFields[field] {
+		return "", &PatchError{Reason: "поле " + field + " нельзя изменить"}
+	}
+
+	switch field {
+	case fieldGroup, fieldTitle, fieldReleaseDate, fieldText, fieldLink:
+		return field, nil
+	default:
+		return "", &PatchError{Reason: "неизвестное поле " + field}
+	}
+}
+
+// songPatchFromFields validates changed fields and converts them into SongPatch
+func songPatchFromFields(fields map[string]interface{}) (models.SongPatch, error) {
+	var patch models.SongPatch
+
+	for field, value := range fields {
+		if readOnlyFields[field] {
+			return models.SongPatch{}, &PatchError{Reason: "поле " + field + " нельзя изменить"}
+		}
+
+		var str *string
+		if value != nil {
+			s, ok := value.(string)
+			if !ok {
+				return models.SongPatch{}, &PatchError{Reason: "поле " + field + " должно быть строкой"}
+			}
+			str = &s
+		}
+
+		switch field {
+		case fieldGroup, fieldTitle:
+			if str == nil || strings.TrimSpace(*str) == "" {
+				return models.SongPatch{}, &PatchError{Reason: "поле " + field + " не может быть пустым"}
+			}
+			if field == fieldGroup {
+				group := strings.TrimSpace(*str)
+				patch.Group = &group
+			} else {
+				patch.Title = str
+			}
+		case fieldReleaseDate:
+			if str == nil {
+				return models.SongPatch{}, &PatchError{Reason: "поле release_date не может быть пустым"}
+			}
+			releaseDate, err := time.Parse("02.01.2006", *str)
+			if err != nil {
+				return models.SongPatch{}, &PatchError{Reason: "неправильный формат даты в поле release_date"}
+			}
+			patch.ReleaseDate = &releaseDate
+		case fieldText, fieldLink:
+			// Removing text or link leaves it empty
+			if str == nil {
+				empty := ""
+				str = &empty
+			}
+			if field == fieldText {
+				patch.Text = str
+			} else {
+				patch.Link = str
+			}
+		default:
+			return models.SongPatch{}, &PatchError{Reason: "неизвестное поле " + field}
+		}
+	}
+
+	return patch, nil
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+var (
+	ErrEmptyPlaylistName = errors.New("playlist name is empty")
+	ErrInvalidPosition   = errors.New("invalid playlist position")
+)
+
+// PlaylistService defines interface for managing playlists and their entries
+type PlaylistService interface {
+	GetPlaylists(ctx context.Context, page, pageSize int) (models.Page[models.Playlist], error)
+	GetPlaylistById(ctx context.Context, id int) (models.PlaylistDetail, error)
+	CreatePlaylist(ctx context.Context, input models.PlaylistRequest) (models.Playlist, error)
+	UpdatePlaylist(ctx context.Context, id int, input models.PlaylistRequest) (models.Playlist, error)
+	DeletePlaylist(ctx context.Context, id int) error
+	AddPlaylistItem(ctx context.Context, playlistID int, input models.PlaylistItemRequest) (models.PlaylistItem, error)
+	MovePlaylistItem(ctx context.Context, playlistID, itemID, position int) (models.PlaylistItem, error)
+	RemovePlaylistItem(ctx context.Context, playlistID, itemID int) error
+}
+
+// GetPlaylists retrieves page of playlists ordered by name using repository
+func (s *SongService) GetPlaylists(ctx context.Context, page, pageSize int) (models.Page[models.Playlist], error) {
+	playlists, err := s.repo.GetPlaylists(ctx, page, pageSize)
+	if err != nil {
+		return models.Page[models.Playlist]{}, err
+	}
+
+	total, err := s.repo.CountPlaylists(ctx)
+	if err != nil {
+		return models.Page[models.Playlist]{}, err
+	}
+
+	result := models.Page[models.Playlist]{Items: playlists, Page: page, PageSize: pageSize}
+	result.SetTotal(total, false)
+	return result, nil
+}
+
+// GetPlaylistById retrieves playlist by ID with its entries and summaries of their songs
+func (s *SongService) GetPlaylistById(ctx context.Context, id int) (models.PlaylistDetail, error) {
+	playlist, err := s.repo.GetPlaylistById(ctx, id)
+	if err != nil {
+		return models.PlaylistDetail{}, err
+	}
+
+	items, err := s.repo.GetPlaylistItems(ctx, id)
+	if err != nil {
+		return models.PlaylistDetail{}, err
+	}
+
+	// Entries may change between queries, count is taken from entries returned
+	playlist.ItemsCount = len(items)
+	return models.PlaylistDetail{Playlist: playlist, Items: items}, nil
+}
+
+// CreatePlaylist creates new empty playlist with trimmed name using repository
+func (s *SongService) CreatePlaylist(ctx context.Context, input models.PlaylistRequest) (models.Playlist, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.Playlist{}, ErrEmptyPlaylistName
+	}
+	return s.repo.CreatePlaylist(ctx, name, strings.TrimSpace(input.Description))
+}
+
+// UpdatePlaylist sets name and description of playlist using repository
+func (s *SongService) UpdatePlaylist(ctx context.Context, id int, input models.PlaylistRequest) (models.Playlist, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.Playlist{}, ErrEmptyPlaylistName
+	}
+	return s.repo.UpdatePlaylist(ctx, id, name, strings.TrimSpace(input.Description))
+}
+
+// DeletePlaylist deletes playlist with all its entries using repository, songs are kept
+func (s *SongService) DeletePlaylist(ctx context.Context, id int) error {
+	return s.repo.DeletePlaylist(ctx, id)
+}
+
+// AddPlaylistItem adds song to playlist at 1-based position, position 0 appends it
+func (s *SongService) AddPlaylistItem(ctx context.Context, playlistID int, input models.PlaylistItemRequest) (models.PlaylistItem, error) {
+	if input.Position < 0 {
+		return models.PlaylistItem{}, ErrInvalidPosition
+	}
+	return s.repo.AddPlaylistItem(ctx, playlistID, input.SongID, input.Position)
+}
+
+// MovePlaylistItem moves entry of playlist to 1-based position, entries between old and new positions shift by one
+func (s *SongService) MovePlaylistItem(ctx context.Context, playlistID, itemID, position int) (models.PlaylistItem, error) {
+	if position < 1 {
+		return models.PlaylistItem{}, ErrInvalidPosition
+	}
+	return s.repo.MovePlaylistItem(ctx, playlistID, itemID, position)
+}
+
+// RemovePlaylistItem removes entry from playlist using repository
+func (s *SongService) RemovePlaylistItem(ctx context.Context, playlistID, itemID int) error {
+	return s.repo.RemovePlaylistItem(ctx, playlistID, itemID)
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+var ErrInvalidRevisionRange = errors.New("invalid revision range")
+
+// RevisionService defines interface for history of song changes
+type RevisionService interface {
+	GetSongRevisions(ctx context.Context, id, page, pageSize int) (models.Page[models.SongRevision], error)
+	GetSongRevision(ctx context.Context, id, revision int) (models.SongRevision, error)
+	DiffSongRevisions(ctx context.Context, id, from, to int) (models.RevisionDiff, error)
+	RevertSongToRevision(ctx context.Context, id, revision, expectedVersion int) (models.Song, error)
+}
+
+// GetSongRevisions retrieves page of song revisions using repository, newest first
+// Song without revisions doesn't exist (or is purged), so ErrSongNotFound is returned for it
+func (s *SongService) GetSongRevisions(ctx context.Context, id, page, pageSize int) (models.Page[models.SongRevision], error) {
+	total, err := s.repo.CountRevisions(ctx, id)
+	if err != nil {
+		return models.Page[models.SongRevision]{}, err
+	}
+	if total == 0 {
+		return models.Page[models.SongRevision]{}, postgresql.ErrSongNotFound
+	}
+
+	revisions, err := s.repo.GetRevisions(ctx, id, page, pageSize)
+	if err != nil {
+		return models.Page[models.SongRevision]{}, err
+	}
+
+	result := models.Page[models.SongRevision]{Items: revisions, Page: page, PageSize: pageSize}
+	result.SetTotal(total, false)
+	return result, nil
+}
+
+// GetSongRevision retrieves single revision of song using repository
+func (s *SongService) GetSongRevision(ctx context.Context, id, revision int) (models.SongRevision, error) {
+	return s.repo.GetRevision(ctx, id, revision)
+}
+
+// DiffSongRevisions compares two revisions of song: changed fields are listed as is,
+// and text is compared line by line. Diff turns revision from into revision to,
+// so from newer than to shows changes backwards
+func (s *SongService) DiffSongRevisions(ctx context.Context, id, from, to int) (models.RevisionDiff, error) {
+	if from < 1 || to < 1 || from == to {
+		return models.RevisionDiff{}, ErrInvalidRevisionRange
+	}
+
+	base, err := s.repo.GetRevision(ctx, id, from)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+	target, err := s.repo.GetRevision(ctx, id, to)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+
+	diff := models.RevisionDiff{
+		SongID: id,
+		From:   from,
+		To:     to,
+		Fields: []models.FieldChange{},
+		Lines:  diffLines(splitLines(base.Text), splitLines(target.Text)),
+	}
+	addChange := func(field string, from, to interface{}, changed bool) {
+		if changed {
+			diff.Fields = append(diff.Fields, models.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	addChange("group", base.Group, target.Group, base.Group != target.Group)
+	addChange("song", base.Title, target.Title, base.Title != target.Title)
+	addChange("release_date", base.ReleaseDate, target.ReleaseDate, !sameDate(base, target))
+	addChange("link", base.Link, target.Link, base.Link != target.Link)
+
+	return diff, nil
+}
+
+// RevertSongToRevision restores group, title, release date, text and link of song from its revision
+// Revert is stored as a new revision, so it can be reverted as well
+func (s *SongService) RevertSongToRevision(ctx context.Context, id, revision, expectedVersion int) (models.Song, error) {
+	s.logger.Infof("RevertSongToRevision[service]: Откат песни ID: %d к ревизии %d", id, revision)
+
+	snapshot, err := s.repo.GetRevision(ctx, id, revision)
+	if err != nil {
+		return models.Song{}, err
+	}
+
+	song := models.Song{
+		Group:       snapshot.Group,
+		Title:       snapshot.Title,
+		ReleaseDate: snapshot.ReleaseDate,
+		Text:        snapshot.Text,
+		Link:        snapshot.Link,
+	}
+	return s.repo.Update(ctx, id, song, expectedVersion)
+}
+
+// sameDate reports whether release dates of revisions are equal, both may be unknown
+func sameDate(a, b models.SongRevision) bool {
+	if a.ReleaseDate == nil || b.ReleaseDate == nil {
+		return a.ReleaseDate == b.ReleaseDate
+	}
+	return a.ReleaseDate.Equal(*b.ReleaseDate)
+}
+
+// splitLines splits text into lines, treating CRLF as line break too
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines returns line diff turning lines a into lines b
+// It uses linear space variant of Myers algorithm, which finds the shortest edit script in O((N+M)D) time
+// and O(N+M) memory, so large texts don't need memory for every round of search
+func diffLines(a, b []string) []models.DiffLine {
+	d := lineDiff{a: a, b: b, lines: []models.DiffLine{}}
+	d.compare(0, 0, len(a), len(b))
+	return d.lines
+}
+
+// lineDiff collects diff of lines a and b, walking both texts from the start
+type lineDiff struct {
+	a, b  []string
+	lines []models.DiffLine
+}
+
+// compare appends diff of a[left:right] and b[top:bottom]
+// Common head and tail are matched right away, the rest is split by middle snake of the shortest edit script
+func (d *lineDiff) compare(left, top, right, bottom int) {
+	for left < right && top < bottom && d.a[left] == d.b[top] {
+		d.equal(left, top)
+		left++
+		top++
+	}
+	tail := 0
+	for left < right-tail && top < bottom-tail && d.a[right-tail-1] == d.b[bottom-tail-1] {
+		tail++
+	}
+	right -= tail
+	bottom -= tail
+
+	switch {
+	case left == right:
+		for y := top; y < bottom; y++ {
+			d.lines = append(d.lines, models.DiffLine{Op: models.DiffInsert, Text: d.b[y], ToLine: y + 1})
+		}
+	case top == bottom:
+		for x := left; x < right; x++ {
+			d.lines = append(d.lines, models.DiffLine{Op: models.DiffDelete, Text: d.a[x], FromLine: x + 1})
+		}
+	default:
+		x, y := d.middleSnake(left, top, right, bottom)
+		d.compare(left, top, x, y)
+		d.compare(x, y, right, bottom)
+	}
+
+	for i := 0; i < tail; i++ {
+		d.equal(right+i, bottom+i)
+	}
+}
+
+// equal appends line present in both texts
+func (d *lineDiff) equal(x, y int) {
+	d.lines = append(d.lines, models.DiffLine{Op: models.DiffEqual, Text: d.a[x], FromLine: x + 1, ToLine: y + 1})
+}
+
+// middleSnake searches the shortest edit script of a[left:right] and b[top:bottom] from both ends at once
+// and returns point where searches meet. Ranges must be non-empty and differ in their first and last lines,
+// then point lies strictly inside of script, so both parts it splits ranges into are smaller
+func (d *lineDiff) middleSnake(left, top, right, bottom int) (int, int) {
+	width, height := right-left, bottom-top
+	delta := width - height
+	limit := (width + height + 1) / 2
+
+	// forward[k] is furthest x reached on diagonal k = x - y counted from (left, top),
+	// backward[c] is furthest y reached on diagonal c = k - delta counted from (right, bottom)
+	offset := limit + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	forward[offset+1] = left
+	backward[offset+1] = bottom
+
+	for step := 0; step <= limit; step++ {
+		for k := step; k >= -step; k -= 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := top + (x - left) - k
+			for x < right && y < bottom && d.a[x] == d.b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			c := k - delta
+			if delta%2 != 0 && c >= -(step-1) && c <= step-1 && y >= backward[offset+c] {
+				return x, y
+			}
+		}
+
+		for c := step; c >= -step; c -= 2 {
+			var y int
+			if c == -step || (c != step && backward[offset+c-1] > backward[offset+c+1]) {
+				y = backward[offset+c+1]
+			} else {
+				y = backward[offset+c-1] - 1
+			}
+			k := c + delta
+			x := left + (y - top) + k
+			for x > left && y > top && d.a[x-1] == d.b[y-1] {
+				x--
+				y--
+			}
+			backward[offset+c] = y
+
+			if delta%2 == 0 && k >= -step && k <= step && x <= forward[offset+k] {
+				return x, y
+			}
+		}
+	}
+	// Searches always meet within limit rounds
+	return left, top
+}
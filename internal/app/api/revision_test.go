@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rest-songs/internal/app/models"
+)
+
+func equalLine(text string, from, to int) models.DiffLine {
+	return models.DiffLine{Op: models.DiffEqual, Text: text, FromLine: from, ToLine: to}
+}
+
+func insertLine(text string, to int) models.DiffLine {
+	return models.DiffLine{Op: models.DiffInsert, Text: text, ToLine: to}
+}
+
+func deleteLine(text string, from int) models.DiffLine {
+	return models.DiffLine{Op: models.DiffDelete, Text: text, FromLine: from}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []models.DiffLine
+	}{
+		{name: "both empty", want: []models.DiffLine{}},
+		{name: "equal", a: "a\nb", b: "a\nb", want: []models.DiffLine{equalLine("a", 1, 1), equalLine("b", 2, 2)}},
+		{name: "from empty", b: "a\nb", want: []models.DiffLine{insertLine("a", 1), insertLine("b", 2)}},
+		{name: "to empty", a: "a\nb", want: []models.DiffLine{deleteLine("a", 1), deleteLine("b", 2)}},
+		{
+			name: "insert in the middle",
+			a:    "a\nc",
+			b:    "a\nb\nc",
+			want: []models.DiffLine{equalLine("a", 1, 1), insertLine("b", 2), equalLine("c", 2, 3)},
+		},
+		{
+			name: "delete in the middle",
+			a:    "a\nb\nc",
+			b:    "a\nc",
+			want: []models.DiffLine{equalLine("a", 1, 1), deleteLine("b", 2), equalLine("c", 3, 2)},
+		},
+		{
+			name: "replace line",
+			a:    "a\nb\nc",
+			b:    "a\nx\nc",
+			want: []models.DiffLine{equalLine("a", 1, 1), deleteLine("b", 2), insertLine("x", 2), equalLine("c", 3, 3)},
+		},
+		{
+			name: "replace all",
+			a:    "a\nb",
+			b:    "x\ny",
+			want: []models.DiffLine{deleteLine("a", 1), deleteLine("b", 2), insertLine("x", 1), insertLine("y", 2)},
+		},
+		{
+			name: "moved line",
+			a:    "a\nb\nc",
+			b:    "b\nc\na",
+			want: []models.DiffLine{deleteLine("a", 1), equalLine("b", 2, 1), equalLine("c", 3, 2), insertLine("a", 3)},
+		},
+		{
+			name: "changes at both ends",
+			a:    "x\na\nb\nc\ny",
+			b:    "a\nb\nc\nz",
+			want: []models.DiffLine{deleteLine("x", 1), equalLine("a", 2, 1), equalLine("b", 3, 2), equalLine("c", 4, 3), deleteLine("y", 5), insertLine("z", 4)},
+		},
+		{
+			name: "CRLF",
+			a:    "a\r\nb",
+			b:    "a\nb\nc",
+			want: []models.DiffLine{equalLine("a", 1, 1), equalLine("b", 2, 2), insertLine("c", 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffLines(splitLines(tt.a), splitLines(tt.b))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("diffLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiffLinesShortest checks that diff is complete and keeps the longest common subsequence of lines
+func TestDiffLinesShortest(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		equal int
+	}{
+		{name: "ABCABBA", a: "a b c a b b a", b: "c b a b a c", equal: 4},
+		{name: "repeated lines", a: "a a a b a a", b: "a b a a a a b", equal: 5},
+		{name: "interleaved", a: "a b c d e f g h", b: "b a d c f e h g", equal: 4},
+		{name: "reversed", a: "a b c d e", b: "e d c b a", equal: 1},
+		{name: "long chorus", a: strings.Repeat("la ", 200) + "end", b: "start " + strings.Repeat("la ", 150), equal: 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+			var fromLines, toLines []string
+			equal := 0
+			for _, line := range diffLines(a, b) {
+				if line.Op != models.DiffInsert {
+					fromLines = append(fromLines, line.Text)
+					if line.FromLine != len(fromLines) {
+						t.Fatalf("line %q has FromLine %d, want %d", line.Text, line.FromLine, len(fromLines))
+					}
+				}
+				if line.Op != models.DiffDelete {
+					toLines = append(toLines, line.Text)
+					if line.ToLine != len(toLines) {
+						t.Fatalf("line %q has ToLine %d, want %d", line.Text, line.ToLine, len(toLines))
+					}
+				}
+				if line.Op == models.DiffEqual {
+					equal++
+				}
+			}
+
+			if !reflect.DeepEqual(fromLines, a) || !reflect.DeepEqual(toLines, b) {
+				t.Fatalf("diff turns %v into %v, want %v into %v", fromLines, toLines, a, b)
+			}
+			if equal != tt.equal {
+				t.Fatalf("diff keeps %d equal lines, want %d", equal, tt.equal)
+			}
+		})
+	}
+}
+
+func TestDiffSongRevisionsRange(t *testing.T) {
+	// Range is checked before repository is used
+	service := New(nil, nil)
+
+	tests := []struct {
+		name     string
+		from, to int
+	}{
+		{name: "same revision", from: 2, to: 2},
+		{name: "zero from", from: 0, to: 2},
+		{name: "zero to", from: 2, to: 0},
+		{name: "negative", from: -1, to: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.DiffSongRevisions(context.Background(), 1, tt.from, tt.to); !errors.Is(err, ErrInvalidRevisionRange) {
+				t.Fatalf("DiffSongRevisions() error = %v, want ErrInvalidRevisionRange", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+var (
+	ErrEmptySearchQuery  = errors.New("search query is empty")
+	ErrInvalidSearchLang = errors.New("invalid search language")
+)
+
+// SearchSongs finds songs by full-text query over title, group and text, ranking results by relevance
+func (s *SongService) SearchSongs(ctx context.Context, search models.SongSearch, filter models.SongFilters, page, pageSize int) ([]models.SongSearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	switch search.Lang {
+	case "", models.SearchLangRussian, models.SearchLangEnglish:
+	default:
+		return nil, ErrInvalidSearchLang
+	}
+
+	if err := validateSongFilters(filter); err != nil {
+		return nil, err
+	}
+	if !filter.AsOf.IsZero() {
+		return nil, &FilterError{Reason: "as_of не поддерживается в поиске"}
+	}
+
+	return s.repo.Search(ctx, search, filter, page, pageSize)
+}
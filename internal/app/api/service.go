@@ -1,11 +1,13 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/lyrics"
 	"rest-songs/internal/app/models"
 	"rest-songs/internal/app/repository/postgresql"
 )
@@ -15,11 +17,28 @@ var ErrPageOutOfBounds = errors.New("page out of bounds")
 // Service defines interface for song service, which includes methods
 // to create, retrieve, update, and delete songs
 type Service interface {
-	GetSongsWithFilter(filter models.SongFilters, page, pageSize int) ([]models.Song, error)
-	GetSongText(id, page, pageSize int) ([]string, error)
-	UpdateSongById(id int, song models.Song) (models.Song, error)
-	DeleteSongById(id int) error
-	CreateSong(group, song string, songDetails models.SongDetail) (models.Song, error)
+	GetSongsWithFilter(ctx context.Context, filter models.SongFilters, sort models.SongSort, page, pageSize int, count string) (models.Page[models.Song], error)
+	GetSongsWithCursor(ctx context.Context, filter models.SongFilters, sort models.SongSort, cursor *models.Cursor, limit int, count string) (models.Page[models.Song], error)
+	GetSongText(ctx context.Context, id int, options models.TextOptions, page, pageSize int) (models.SongText[models.Verse], error)
+	GetSongLines(ctx context.Context, id int, options models.TextOptions, page, pageSize int) (models.SongText[models.LyricsLine], error)
+	GetSongById(ctx context.Context, id int) (models.Song, error)
+	UpdateSongById(ctx context.Context, id int, song models.Song, expectedVersion int) (models.Song, error)
+	PatchSongById(ctx context.Context, id int, patch models.SongPatch, expectedVersion int) (models.Song, error)
+	DeleteSongById(ctx context.Context, id int, expectedVersion int) error
+	CreateSong(ctx context.Context, group, song string, songDetails models.SongDetail) (models.Song, error)
+	EnqueueSong(ctx context.Context, group, song string) (models.Song, error)
+	GetSongEnrichment(ctx context.Context, id int) (models.SongEnrichment, error)
+	SearchSongs(ctx context.Context, search models.SongSearch, filter models.SongFilters, page, pageSize int) ([]models.SongSearchResult, error)
+
+	ArtistService
+	TrashService
+	RevisionService
+	ImportService
+	ExportService
+	SyncedLyricsService
+	ChordService
+	PlaylistService
+	APIKeyService
 }
 
 // SongService is implementation of Service interface
@@ -37,55 +56,168 @@ func New(repo postgresql.Repository, logger *logrus.Logger) *SongService {
 	}
 }
 
-// GetSongsWithFilter retrieves list of all songs from repository with given filters
-func (s *SongService) GetSongsWithFilter(filter models.SongFilters, page, pageSize int) ([]models.Song, error) {
-	return s.repo.GetWithFilter(filter, page, pageSize)
+// GetSongsWithFilter retrieves page of songs from repository with given filters in given order
+// Total number of songs is counted according to count mode
+// It returns *FilterError if filter or sort is inconsistent
+func (s *SongService) GetSongsWithFilter(ctx context.Context, filter models.SongFilters, sort models.SongSort, page, pageSize int, count string) (models.Page[models.Song], error) {
+	if err := validateSongFilters(filter); err != nil {
+		s.logger.Warnf("GetSongsWithFilter[service]: Неправильный фильтр: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+	if err := validateSongSort(sort); err != nil {
+		s.logger.Warnf("GetSongsWithFilter[service]: Неправильная сортировка: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+
+	songs, err := s.repo.GetWithFilter(ctx, filter, sort, page, pageSize)
+	if err != nil {
+		return models.Page[models.Song]{}, err
+	}
+
+	result := models.Page[models.Song]{Items: songs, Page: page, PageSize: pageSize}
+	if err = s.countSongs(ctx, &result, filter, count); err != nil {
+		return models.Page[models.Song]{}, err
+	}
+	return result, nil
+}
+
+// GetSongsWithCursor retrieves page of songs following or preceding cursor with given filters in given order
+// Without cursor the first page is returned
+func (s *SongService) GetSongsWithCursor(ctx context.Context, filter models.SongFilters, sort models.SongSort, cursor *models.Cursor, limit int, count string) (models.Page[models.Song], error) {
+	if err := validateSongFilters(filter); err != nil {
+		s.logger.Warnf("GetSongsWithCursor[service]: Неправильный фильтр: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+	if err := validateSongSort(sort); err != nil {
+		s.logger.Warnf("GetSongsWithCursor[service]: Неправильная сортировка: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+
+	result, err := s.repo.GetWithCursor(ctx, filter, sort, cursor, limit)
+	if err != nil {
+		return models.Page[models.Song]{}, err
+	}
+
+	if err = s.countSongs(ctx, &result, filter, count); err != nil {
+		return models.Page[models.Song]{}, err
+	}
+	return result, nil
+}
+
+// countSongs sets total number of songs matching filter on page according to count mode
+func (s *SongService) countSongs(ctx context.Context, page *models.Page[models.Song], filter models.SongFilters, count string) error {
+	if count == models.CountNone {
+		return nil
+	}
+
+	estimate := count == models.CountEstimated
+	total, err := s.repo.Count(ctx, filter, estimate)
+	if err != nil {
+		s.logger.Errorf("countSongs[service]: Ошибка подсчета песен: %v", err)
+		return err
+	}
+
+	page.SetTotal(total, estimate)
+	return nil
+}
+
+// GetSongText retrieves text of song by its ID split into verses, with support for pagination
+func (s *SongService) GetSongText(ctx context.Context, id int, options models.TextOptions, page, pageSize int) (models.SongText[models.Verse], error) {
+	s.logger.Infof("GetSongText[service]: Получение куплетов песни ID: %d, страница: %d, размер страницы: %d", id, page, pageSize)
+	song, verses, err := s.songVerses(ctx, id, options)
+	if err != nil {
+		return models.SongText[models.Verse]{}, err
+	}
+
+	return textPage(verses, song, page, pageSize)
+}
+
+// GetSongLines retrieves text of song by its ID split into lines, with support for pagination
+// Lines of repeated verses are left out when repeats are collapsed
+func (s *SongService) GetSongLines(ctx context.Context, id int, options models.TextOptions, page, pageSize int) (models.SongText[models.LyricsLine], error) {
+	s.logger.Infof("GetSongLines[service]: Получение строк песни ID: %d, страница: %d, размер страницы: %d", id, page, pageSize)
+	song, verses, err := s.songVerses(ctx, id, options)
+	if err != nil {
+		return models.SongText[models.LyricsLine]{}, err
+	}
+
+	return textPage(lyrics.Lines(verses), song, page, pageSize)
 }
 
-// GetSongText retrieves text of song by its ID, with support for pagination
-// It returns slice of strings representing verses of song
-func (s *SongService) GetSongText(id, page, pageSize int) ([]string, error) {
-	s.logger.Infof("GetSongText[service]: Получение текста песни ID: %d, страница: %d, размер страницы: %d", id, page, pageSize)
-	song, err := s.repo.GetById(id)
+// songVerses retrieves current song or song as it was at options.AsOf and parses its text into verses
+func (s *SongService) songVerses(ctx context.Context, id int, options models.TextOptions) (models.Song, []models.Verse, error) {
+	var song models.Song
+	var err error
+	if options.AsOf.IsZero() {
+		song, err = s.repo.GetById(ctx, id)
+	} else {
+		song, err = s.repo.GetByIdAsOf(ctx, id, options.AsOf)
+	}
 	if err != nil {
-		s.logger.Errorf("GetSongText[service]: Ошибка получения песни по ID %d: %v", id, err)
-		return nil, err
+		s.logger.Errorf("songVerses[service]: Ошибка получения песни по ID %d: %v", id, err)
+		return models.Song{}, nil, err
 	}
 
-	verses := strings.Split(song.Text, "\n\n")
+	verses := lyrics.Parse(song.Text)
+	if options.CollapseRepeats {
+		verses = lyrics.Collapse(verses)
+	}
+	return song, verses, nil
+}
 
+// textPage returns requested page of verses or lines of song text
+// Page right after the last one is empty, pages further are out of bounds
+func textPage[T any](items []T, song models.Song, page, pageSize int) (models.SongText[T], error) {
 	// Calculate pagination boundaries
 	start := (page - 1) * pageSize
 	end := start + pageSize
 
-	if start > len(verses) {
-		s.logger.Warnf("GetSongText[service]: Страница %d выходит за пределы текста", page)
-		return nil, ErrPageOutOfBounds
+	if start > len(items) {
+		return models.SongText[T]{}, ErrPageOutOfBounds
 	}
 
-	if end > len(verses) {
-		end = len(verses)
+	if end > len(items) {
+		end = len(items)
 	}
 
-	// Return appropriate verses for requested page
-	s.logger.Infof("GetSongText[service]: Успешно получено %d строк текста", end-start)
-	return verses[start:end], nil
+	result := models.SongText[T]{
+		Page:      models.Page[T]{Items: items[start:end], Page: page, PageSize: pageSize},
+		Version:   song.Version,
+		UpdatedAt: song.UpdatedAt,
+	}
+	result.SetTotal(int64(len(items)), false)
+	return result, nil
 }
 
 // UpdateSongById updates an existing song by ID using repository
-// and returns updated song
-func (s *SongService) UpdateSongById(id int, song models.Song) (models.Song, error) {
-	return s.repo.Update(id, song)
+// and returns updated song. Non-zero expectedVersion must match current version of song
+func (s *SongService) UpdateSongById(ctx context.Context, id int, song models.Song, expectedVersion int) (models.Song, error) {
+	song.Group = strings.TrimSpace(song.Group)
+	return s.repo.Update(ctx, id, song, expectedVersion)
+}
+
+// GetSongById retrieves song by ID using repository
+func (s *SongService) GetSongById(ctx context.Context, id int) (models.Song, error) {
+	return s.repo.GetById(ctx, id)
+}
+
+// PatchSongById updates only fields of song set in patch using repository
+// and returns updated song. Non-zero expectedVersion must match current version of song
+func (s *SongService) PatchSongById(ctx context.Context, id int, patch models.SongPatch, expectedVersion int) (models.Song, error) {
+	s.logger.Infof("PatchSongById[service]: Частичное обновление песни ID: %d", id)
+	return s.repo.Patch(ctx, id, patch, expectedVersion)
 }
 
-// DeleteSongById deletes song by ID using repository
-func (s *SongService) DeleteSongById(id int) error {
-	return s.repo.Delete(id)
+// DeleteSongById moves song to trash by ID using repository
+// Non-zero expectedVersion must match current version of song
+func (s *SongService) DeleteSongById(ctx context.Context, id int, expectedVersion int) error {
+	return s.repo.Delete(ctx, id, expectedVersion)
 }
 
 // CreateSong creates new song using repository and returns created song
-func (s *SongService) CreateSong(group, song string, songDetails models.SongDetail) (models.Song, error) {
+func (s *SongService) CreateSong(ctx context.Context, group, song string, songDetails models.SongDetail) (models.Song, error) {
 	s.logger.Infof("CreateSong[service]: Создание песни группы: %s, название: %s", group, song)
+	group = strings.TrimSpace(group)
 
 	// Parse release date from string to time.Time format
 	releaseDate, err := time.Parse("02.01.2006", songDetails.ReleaseDate)
@@ -97,12 +229,12 @@ func (s *SongService) CreateSong(group, song string, songDetails models.SongDeta
 	newSong := models.Song{
 		Group:       group,
 		Title:       song,
-		ReleaseDate: releaseDate,
+		ReleaseDate: &releaseDate,
 		Text:        songDetails.Text,
 		Link:        songDetails.Link,
 	}
 
-	createdSong, err := s.repo.Create(newSong)
+	createdSong, err := s.repo.Create(ctx, newSong)
 	if err != nil {
 		s.logger.Errorf("CreateSong[service]: Ошибка создания песни в базе: %v", err)
 		return models.Song{}, err
@@ -111,3 +243,24 @@ func (s *SongService) CreateSong(group, song string, songDetails models.SongDeta
 	s.logger.Infof("CreateSong[service]: Песня успешно создана: %+v", createdSong)
 	return createdSong, nil
 }
+
+// EnqueueSong creates song without details and queues it for background enrichment
+// It returns created song with pending enrichment status
+func (s *SongService) EnqueueSong(ctx context.Context, group, song string) (models.Song, error) {
+	s.logger.Infof("EnqueueSong[service]: Постановка песни в очередь, группа: %s, название: %s", group, song)
+	group = strings.TrimSpace(group)
+
+	createdSong, err := s.repo.CreatePending(ctx, models.Song{Group: group, Title: song})
+	if err != nil {
+		s.logger.Errorf("EnqueueSong[service]: Ошибка создания песни в базе: %v", err)
+		return models.Song{}, err
+	}
+
+	s.logger.Infof("EnqueueSong[service]: Песня поставлена в очередь: %+v", createdSong)
+	return createdSong, nil
+}
+
+// GetSongEnrichment retrieves enrichment status and last error of song by its ID
+func (s *SongService) GetSongEnrichment(ctx context.Context, id int) (models.SongEnrichment, error) {
+	return s.repo.GetEnrichment(ctx, id)
+}
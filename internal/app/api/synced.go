@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"rest-songs/internal/app/lyrics"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+var ErrSyncedLyricsNotFound = errors.New("synced lyrics not found")
+
+// SyncError describes timings that don't fit text of song
+// Reason is human readable and is returned to client as is
+type SyncError struct {
+	Reason string
+}
+
+func (e *SyncError) Error() string {
+	return "invalid synced lyrics: " + e.Reason
+}
+
+// SyncedLyricsService defines interface for time-synchronized text of songs
+type SyncedLyricsService interface {
+	GetSyncedLyrics(ctx context.Context, id int) (models.SyncedLyrics, error)
+	SetSyncedLyrics(ctx context.Context, id int, offsets []int, expectedVersion int) (models.SyncedLyrics, error)
+	ImportSongLRC(ctx context.Context, id int, r io.Reader, expectedVersion int) (models.SyncedLyrics, error)
+}
+
+// GetSyncedLyrics retrieves text of song by its ID with timing of every line
+func (s *SongService) GetSyncedLyrics(ctx context.Context, id int) (models.SyncedLyrics, error) {
+	s.logger.Infof("GetSyncedLyrics[service]: Получение синхронизированного текста песни ID: %d", id)
+	song, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.SyncedLyrics{}, err
+	}
+
+	offsets, err := s.repo.GetLyricsTimings(ctx, id)
+	if err != nil {
+		return models.SyncedLyrics{}, err
+	}
+
+	lines := lyrics.Lines(lyrics.Parse(song.Text))
+	// Timings are dropped together with text change, so mismatch means there are no timings
+	if len(offsets) == 0 || len(offsets) != len(lines) {
+		return models.SyncedLyrics{}, ErrSyncedLyricsNotFound
+	}
+	return syncedLyrics(song, lines, offsets), nil
+}
+
+// SetSyncedLyrics sets timings of song text lines, i-th offset in milliseconds belongs to line i+1
+// Every line must have its timing, timings must not decrease and can't be later than MaxOffsetMs.
+// Non-zero expectedVersion must match current version of song
+func (s *SongService) SetSyncedLyrics(ctx context.Context, id int, offsets []int, expectedVersion int) (models.SyncedLyrics, error) {
+	s.logger.Infof("SetSyncedLyrics[service]: Сохранение таймингов песни ID: %d", id)
+	song, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.SyncedLyrics{}, err
+	}
+	if expectedVersion != 0 && expectedVersion != song.Version {
+		return models.SyncedLyrics{}, postgresql.ErrVersionMismatch
+	}
+
+	lines := lyrics.Lines(lyrics.Parse(song.Text))
+	if len(lines) == 0 {
+		return models.SyncedLyrics{}, &SyncError{Reason: "у песни нет текста"}
+	}
+	if len(offsets) != len(lines) {
+		return models.SyncedLyrics{}, &SyncError{
+			Reason: fmt.Sprintf("количество строк не совпадает с текстом песни: ожидается %d, получено %d", len(lines), len(offsets)),
+		}
+	}
+	for i, offset := range offsets {
+		if offset < 0 {
+			return models.SyncedLyrics{}, &SyncError{Reason: fmt.Sprintf("строка %d: время не может быть отрицательным", i+1)}
+		}
+		if offset > models.MaxOffsetMs {
+			return models.SyncedLyrics{}, &SyncError{
+				Reason: fmt.Sprintf("строка %d: время не может быть больше %d мс", i+1, models.MaxOffsetMs),
+			}
+		}
+		if i > 0 && offset < offsets[i-1] {
+			return models.SyncedLyrics{}, &SyncError{Reason: fmt.Sprintf("строка %d: время меньше времени предыдущей строки", i+1)}
+		}
+	}
+
+	if err = s.repo.SaveLyricsTimings(ctx, id, song.Version, offsets); err != nil {
+		return models.SyncedLyrics{}, err
+	}
+	return syncedLyrics(song, lines, offsets), nil
+}
+
+// ImportSongLRC sets timings of song text lines from LRC document
+// Lines of document are matched to lines of song text by their order, see SetSyncedLyrics
+func (s *SongService) ImportSongLRC(ctx context.Context, id int, r io.Reader, expectedVersion int) (models.SyncedLyrics, error) {
+	lines, err := lyrics.ParseLRC(r)
+	if err != nil {
+		s.logger.Errorf("ImportSongLRC[service]: Ошибка разбора LRC песни ID %d: %v", id, err)
+		return models.SyncedLyrics{}, err
+	}
+
+	offsets := make([]int, len(lines))
+	for i, line := range lines {
+		offsets[i] = line.OffsetMs
+	}
+	return s.SetSyncedLyrics(ctx, id, offsets, expectedVersion)
+}
+
+// syncedLyrics joins lines of song text with their timings
+func syncedLyrics(song models.Song, lines []models.LyricsLine, offsets []int) models.SyncedLyrics {
+	result := models.SyncedLyrics{
+		SongID:  song.ID,
+		Group:   song.Group,
+		Title:   song.Title,
+		Version: song.Version,
+		Lines:   make([]models.SyncedLine, len(lines)),
+	}
+	for i, line := range lines {
+		result.Lines[i] = models.SyncedLine{Number: line.Number, Verse: line.Verse, Text: line.Text, OffsetMs: offsets[i]}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"rest-songs/internal/app/models"
+)
+
+// TrashService defines interface for songs moved to trash by DeleteSongById
+type TrashService interface {
+	GetDeletedSongs(ctx context.Context, page, pageSize int) (models.Page[models.Song], error)
+	RestoreSongById(ctx context.Context, id int) (models.Song, error)
+}
+
+// GetDeletedSongs retrieves page of songs in trash using repository, most recently deleted first
+func (s *SongService) GetDeletedSongs(ctx context.Context, page, pageSize int) (models.Page[models.Song], error) {
+	songs, err := s.repo.GetDeleted(ctx, page, pageSize)
+	if err != nil {
+		return models.Page[models.Song]{}, err
+	}
+
+	total, err := s.repo.CountDeleted(ctx)
+	if err != nil {
+		s.logger.Errorf("GetDeletedSongs[service]: Ошибка подсчета песен в корзине: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+
+	result := models.Page[models.Song]{Items: songs, Page: page, PageSize: pageSize}
+	result.SetTotal(total, false)
+	return result, nil
+}
+
+// RestoreSongById takes song out of trash by ID using repository and returns restored song
+func (s *SongService) RestoreSongById(ctx context.Context, id int) (models.Song, error) {
+	s.logger.Infof("RestoreSongById[service]: Восстановление песни ID: %d", id)
+	return s.repo.Restore(ctx, id)
+}
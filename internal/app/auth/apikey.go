@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// apiKeyPrefix starts every API key, so keys are easy to recognize in configs and logs
+const apiKeyPrefix = "rs_"
+
+// apiKeyDisplayLength is length of the beginning of key stored in clear to tell keys apart
+const apiKeyDisplayLength = len(apiKeyPrefix) + 8
+
+// GenerateAPIKey returns new random API key and the beginning of it to display
+func GenerateAPIKey() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// HashAPIKey returns SHA-256 hash of API key, under which key is stored
+// Keys are random, so unsalted fast hash is enough to make leaked hashes useless
+func HashAPIKey(key string) []byte {
+	hash := sha256.Sum256([]byte(key))
+	return hash[:]
+}
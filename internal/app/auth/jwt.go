@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// tokenLeeway is allowed clock skew between token issuer and server
+const tokenLeeway = 30 * time.Second
+
+// VerifierConfig holds keys and expected claims of JWT
+// HS256Secret is shared secret without key ID, KeysFile is path to JSON Web Key Set
+// with "oct" keys for HS256 and "RSA" public keys for RS256. Empty Issuer and Audience are not checked
+type VerifierConfig struct {
+	HS256Secret string
+	KeysFile    string
+	Issuer      string
+	Audience    string
+}
+
+// Verifier verifies HS256 and RS256 signed JWT against locally configured keys
+type Verifier struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// jsonWebKey is key of JSON Web Key Set, only fields of symmetric and RSA keys are read
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// tokenHeader is JOSE header of JWT
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// tokenClaims are registered claims of JWT and role of client
+// Audience may be string or array of strings, numeric dates may be fractional
+type tokenClaims struct {
+	Subject   string          `json:"sub"`
+	Role      string          `json:"role"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+}
+
+// NewVerifier creates Verifier with keys from config
+func NewVerifier(cfg VerifierConfig) (*Verifier, error) {
+	v := &Verifier{
+		hmacKeys: map[string][]byte{},
+		rsaKeys:  map[string]*rsa.PublicKey{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		now:      time.Now,
+	}
+	if cfg.HS256Secret != "" {
+		v.hmacKeys[""] = []byte(cfg.HS256Secret)
+	}
+	if cfg.KeysFile != "" {
+		if err := v.loadKeySet(cfg.KeysFile); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// loadKeySet reads JSON Web Key Set from file
+func (v *Verifier) loadKeySet(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать набор ключей JWT: %w", err)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("неправильный формат набора ключей JWT: %w", err)
+	}
+
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		switch key.Kty {
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.K, "="))
+			if err != nil || len(secret) == 0 {
+				return fmt.Errorf("ключ %d набора ключей JWT: неправильное значение k", i)
+			}
+			v.hmacKeys[key.Kid] = secret
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.N, "="))
+			e, errE := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.E, "="))
+			if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+				return fmt.Errorf("ключ %d набора ключей JWT: неправильные значения n и e", i)
+			}
+			v.rsaKeys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		default:
+			return fmt.Errorf("ключ %d набора ключей JWT: неподдерживаемый тип %q", i, key.Kty)
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether any key is configured, without keys every token is rejected
+func (v *Verifier) Enabled() bool {
+	return len(v.hmacKeys) > 0 || len(v.rsaKeys) > 0
+}
+
+// Verify checks signature and claims of token and returns principal it was issued for
+// Algorithm is taken from header, but key must be of matching type, so "none" and
+// HS256 tokens signed with RSA public key are rejected. Token must expire and have known role
+func (v *Verifier) Verify(token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, fmt.Errorf("%w: ожидается три части", ErrInvalidToken)
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Principal{}, fmt.Errorf("%w: неправильный заголовок", ErrInvalidToken)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: неправильная подпись", ErrInvalidToken)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	if !v.verifySignature(header, signed, signature) {
+		return Principal{}, fmt.Errorf("%w: подпись не подходит ни к одному ключу %s", ErrInvalidToken, header.Alg)
+	}
+
+	var claims tokenClaims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: неправильные claims", ErrInvalidToken)
+	}
+	if err = v.checkClaims(claims); err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{Subject: claims.Subject, Role: claims.Role, Method: MethodJWT}, nil
+}
+
+// verifySignature checks signature with key from header kid, or with every key of algorithm without kid
+func (v *Verifier) verifySignature(header tokenHeader, signed, signature []byte) bool {
+	digest := sha256.Sum256(signed)
+
+	switch header.Alg {
+	case "HS256":
+		for kid, secret := range v.hmacKeys {
+			if header.Kid != "" && kid != header.Kid {
+				continue
+			}
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(signed)
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return true
+			}
+		}
+	case "RS256":
+		for kid, key := range v.rsaKeys {
+			if header.Kid != "" && kid != header.Kid {
+				continue
+			}
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkClaims checks expiration, issuer, audience and role of token
+func (v *Verifier) checkClaims(claims tokenClaims) error {
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("%w: нет срока действия exp", ErrInvalidToken)
+	}
+	if now.Add(-tokenLeeway).After(numericDate(*claims.ExpiresAt)) {
+		return fmt.Errorf("%w: срок действия истек", ErrInvalidToken)
+	}
+	if claims.NotBefore != nil && now.Add(tokenLeeway).Before(numericDate(*claims.NotBefore)) {
+		return fmt.Errorf("%w: токен еще не действителен", ErrInvalidToken)
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: неверный издатель %q", ErrInvalidToken, claims.Issuer)
+	}
+	if v.audience != "" && !hasAudience(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: токен выпущен не для этого сервиса", ErrInvalidToken)
+	}
+	if !ValidRole(claims.Role) {
+		return fmt.Errorf("%w: неизвестная роль %q", ErrInvalidToken, claims.Role)
+	}
+	return nil
+}
+
+// hasAudience reports whether aud claim, string or array of strings, contains audience
+func hasAudience(raw json.RawMessage, audience string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == audience
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		for _, aud := range list {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// numericDate converts JWT numeric date, seconds since epoch, to time
+func numericDate(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+// decodeSegment decodes base64url encoded JSON segment of token
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSecret   = "test-secret"
+	testIssuer   = "https://issuer.example"
+	testAudience = "rest-songs"
+)
+
+var testNow = time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+
+// testKeys holds keys of JSON Web Key Set used in tests
+type testKeys struct {
+	rsa    *rsa.PrivateKey
+	octKid string
+	oct    []byte
+	rsaKid string
+}
+
+// newTestKeys generates RSA key and writes key set with it and symmetric key to temporary file
+func newTestKeys(t *testing.T) (testKeys, string) {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	keys := testKeys{rsa: private, rsaKid: "rsa-1", octKid: "oct-1", oct: []byte("oct-secret")}
+
+	set := map[string]interface{}{"keys": []map[string]string{
+		{"kty": "oct", "kid": keys.octKid, "use": "sig", "k": base64.RawURLEncoding.EncodeToString(keys.oct)},
+		{
+			"kty": "RSA",
+			"kid": keys.rsaKid,
+			"n":   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+		},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "", "e": ""},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal key set: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write key set: %v", err)
+	}
+	return keys, path
+}
+
+// newTestVerifier creates Verifier with test keys, issuer and audience and fixed clock
+func newTestVerifier(t *testing.T, cfg VerifierConfig) *Verifier {
+	t.Helper()
+
+	v, err := NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+// signToken builds token with given header and claims, signed by sign
+func signToken(t *testing.T, header, claims map[string]interface{}, sign func(signed []byte) []byte) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal token segment: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func hs256(secret []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("sign RS256: %v", err)
+		}
+		return signature
+	}
+}
+
+func unsigned([]byte) []byte {
+	return nil
+}
+
+// claims returns valid claims of editor token, changed by given overrides; nil override removes claim
+func claims(overrides map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{
+		"sub":  "client",
+		"role": "editor",
+		"iss":  testIssuer,
+		"aud":  testAudience,
+		"exp":  testNow.Add(time.Hour).Unix(),
+		"nbf":  testNow.Add(-time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(c, name)
+			continue
+		}
+		c[name] = value
+	}
+	return c
+}
+
+func TestVerify(t *testing.T) {
+	keys, keysFile := newTestKeys(t)
+	v := newTestVerifier(t, VerifierConfig{
+		HS256Secret: testSecret,
+		KeysFile:    keysFile,
+		Issuer:      testIssuer,
+		Audience:    testAudience,
+	})
+
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPKIX(t, &keys.rsa.PublicKey)})
+
+	hsHeader := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	rsHeader := map[string]interface{}{"alg": "RS256", "kid": keys.rsaKid}
+	secret := []byte(testSecret)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "HS256 with configured secret", token: signToken(t, hsHeader, claims(nil), hs256(secret))},
+		{name: "HS256 with key of set", token: signToken(t, map[string]interface{}{"alg": "HS256", "kid": keys.octKid}, claims(nil), hs256(keys.oct))},
+		{name: "RS256 with key id", token: signToken(t, rsHeader, claims(nil), rs256(t, keys.rsa))},
+		{name: "RS256 without key id", token: signToken(t, map[string]interface{}{"alg": "RS256"}, claims(nil), rs256(t, keys.rsa))},
+		{name: "alg none", token: signToken(t, map[string]interface{}{"alg": "none"}, claims(nil), unsigned), wantErr: true},
+		{name: "alg none with HS256 signature", token: signToken(t, map[string]interface{}{"alg": "none"}, claims(nil), hs256(secret)), wantErr: true},
+		{name: "HS256 signed with RSA public key", token: signToken(t, hsHeader, claims(nil), hs256(publicPEM)), wantErr: true},
+		{name: "HS256 signed with RSA modulus", token: signToken(t, hsHeader, claims(nil), hs256(keys.rsa.N.Bytes())), wantErr: true},
+		{name: "RS256 with wrong key id", token: signToken(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-2"}, claims(nil), rs256(t, keys.rsa)), wantErr: true},
+		{name: "RS256 with key id of symmetric key", token: signToken(t, map[string]interface{}{"alg": "RS256", "kid": keys.octKid}, claims(nil), rs256(t, keys.rsa)), wantErr: true},
+		{name: "HS256 with key id of other key", token: signToken(t, map[string]interface{}{"alg": "HS256", "kid": keys.octKid}, claims(nil), hs256(secret)), wantErr: true},
+		{name: "RS256 key of encryption", token: signToken(t, map[string]interface{}{"alg": "RS256", "kid": "enc-1"}, claims(nil), rs256(t, keys.rsa)), wantErr: true},
+		{name: "tampered claims", token: tamper(t, signToken(t, hsHeader, claims(nil), hs256(secret)), claims(map[string]interface{}{"role": "admin"})), wantErr: true},
+		{name: "two parts", token: "a.b", wantErr: true},
+		{name: "expired within leeway", token: signToken(t, hsHeader, claims(map[string]interface{}{"exp": testNow.Add(-tokenLeeway + time.Second).Unix()}), hs256(secret))},
+		{name: "expired beyond leeway", token: signToken(t, hsHeader, claims(map[string]interface{}{"exp": testNow.Add(-tokenLeeway - time.Second).Unix()}), hs256(secret)), wantErr: true},
+		{name: "fractional exp", token: signToken(t, hsHeader, claims(map[string]interface{}{"exp": float64(testNow.Unix()) + 0.5}), hs256(secret))},
+		{name: "without exp", token: signToken(t, hsHeader, claims(map[string]interface{}{"exp": nil}), hs256(secret)), wantErr: true},
+		{name: "not before within leeway", token: signToken(t, hsHeader, claims(map[string]interface{}{"nbf": testNow.Add(tokenLeeway - time.Second).Unix()}), hs256(secret))},
+		{name: "not before beyond leeway", token: signToken(t, hsHeader, claims(map[string]interface{}{"nbf": testNow.Add(tokenLeeway + time.Second).Unix()}), hs256(secret)), wantErr: true},
+		{name: "without nbf", token: signToken(t, hsHeader, claims(map[string]interface{}{"nbf": nil}), hs256(secret))},
+		{name: "audience array", token: signToken(t, hsHeader, claims(map[string]interface{}{"aud": []string{"other", testAudience}}), hs256(secret))},
+		{name: "audience array without service", token: signToken(t, hsHeader, claims(map[string]interface{}{"aud": []string{"other"}}), hs256(secret)), wantErr: true},
+		{name: "other audience", token: signToken(t, hsHeader, claims(map[string]interface{}{"aud": "other"}), hs256(secret)), wantErr: true},
+		{name: "without audience", token: signToken(t, hsHeader, claims(map[string]interface{}{"aud": nil}), hs256(secret)), wantErr: true},
+		{name: "other issuer", token: signToken(t, hsHeader, claims(map[string]interface{}{"iss": "https://other.example"}), hs256(secret)), wantErr: true},
+		{name: "unknown role", token: signToken(t, hsHeader, claims(map[string]interface{}{"role": "owner"}), hs256(secret)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := v.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			want := Principal{Subject: "client", Role: "editor", Method: MethodJWT}
+			if principal != want {
+				t.Fatalf("Verify() = %+v, want %+v", principal, want)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutIssuerAndAudience(t *testing.T) {
+	v := newTestVerifier(t, VerifierConfig{HS256Secret: testSecret})
+	token := signToken(t, map[string]interface{}{"alg": "HS256"},
+		claims(map[string]interface{}{"iss": nil, "aud": nil}), hs256([]byte(testSecret)))
+
+	if _, err := v.Verify(token); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+}
+
+func TestVerifyWithoutKeys(t *testing.T) {
+	v := newTestVerifier(t, VerifierConfig{})
+	if v.Enabled() {
+		t.Fatal("Enabled() = true without keys")
+	}
+
+	token := signToken(t, map[string]interface{}{"alg": "HS256"}, claims(nil), hs256(nil))
+	if _, err := v.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+	}
+}
+
+// tamper replaces claims of signed token keeping its header and signature
+func tamper(t *testing.T, token string, claims map[string]interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(data) + "." + parts[2]
+}
+
+func mustMarshalPKIX(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	return der
+}
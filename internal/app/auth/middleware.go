@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+)
+
+// KeyAuthenticator looks up principal of API key stored in database
+// ErrInvalidCredentials is returned for unknown and revoked keys
+type KeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (Principal, error)
+}
+
+// Config holds configuration of authentication
+// Disabled authentication lets every request through. BootstrapAdminKey is admin API key given
+// in configuration, so that first keys can be issued. Requests to Public path prefixes are not authenticated
+type Config struct {
+	Enabled           bool
+	BootstrapAdminKey string
+	Public            []string
+}
+
+// Authenticator authenticates requests by API key or JWT and checks role required for them
+type Authenticator struct {
+	keys          KeyAuthenticator
+	verifier      *Verifier
+	cfg           Config
+	bootstrapHash []byte
+	logger        *logrus.Logger
+}
+
+// New creates new Authenticator
+func New(keys KeyAuthenticator, verifier *Verifier, cfg Config, logger *logrus.Logger) *Authenticator {
+	a := &Authenticator{keys: keys, verifier: verifier, cfg: cfg, logger: logger}
+	if cfg.BootstrapAdminKey != "" {
+		a.bootstrapHash = HashAPIKey(cfg.BootstrapAdminKey)
+	}
+	return a
+}
+
+// Middleware authenticates request and puts its principal into request context
+// Credentials are taken from "Authorization: Bearer" header, which may hold either API key or JWT,
+// or from X-API-Key header. Missing or invalid credentials get 401, insufficient role gets 403
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.cfg.Enabled || a.public(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		credentials := credentials(r)
+		if credentials == "" {
+			writeUnauthorized(w, "Требуется аутентификация")
+			return
+		}
+
+		principal, err := a.authenticate(r.Context(), credentials)
+		if err != nil {
+			if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrInvalidToken) {
+				a.logger.Warnf("Middleware[auth]: Отказ в аутентификации %s %s: %v", r.Method, r.URL.Path, err)
+				writeUnauthorized(w, "Неверный ключ или токен")
+				return
+			}
+			a.logger.Errorf("Middleware[auth]: Ошибка аутентификации: %v", err)
+			http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+			return
+		}
+
+		if required := RequiredRole(r); !principal.Allows(required) {
+			a.logger.Warnf("Middleware[auth]: %s с ролью %s не может выполнить %s %s",
+				principal.Subject, principal.Role, r.Method, r.URL.Path)
+			http.Error(w, "Недостаточно прав: требуется роль "+required, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// authenticate returns principal of API key or JWT
+func (a *Authenticator) authenticate(ctx context.Context, credentials string) (Principal, error) {
+	// JWT consists of three dot separated parts, API keys have no dots
+	if strings.Count(credentials, ".") == 2 {
+		return a.verifier.Verify(credentials)
+	}
+
+	if a.bootstrapHash != nil && subtle.ConstantTimeCompare(HashAPIKey(credentials), a.bootstrapHash) == 1 {
+		return Principal{Subject: "bootstrap", Role: models.RoleAdmin, Method: MethodAPIKey}, nil
+	}
+	return a.keys.AuthenticateAPIKey(ctx, credentials)
+}
+
+// public reports whether path doesn't require authentication
+func (a *Authenticator) public(path string) bool {
+	for _, prefix := range a.cfg.Public {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// credentials returns API key or token of request
+func credentials(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return strings.TrimSpace(key)
+	}
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// writeUnauthorized responds with 401 and challenge for Bearer scheme
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="rest-songs"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+)
+
+// stubKeys authenticates API keys from map
+type stubKeys map[string]Principal
+
+func (s stubKeys) AuthenticateAPIKey(_ context.Context, key string) (Principal, error) {
+	principal, ok := s[key]
+	if !ok {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return principal, nil
+}
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/songs", models.RoleReader},
+		{http.MethodHead, "/songs/1", models.RoleReader},
+		{http.MethodOptions, "/songs", models.RoleReader},
+		{http.MethodPost, "/songs", models.RoleEditor},
+		{http.MethodPut, "/songs/1", models.RoleEditor},
+		{http.MethodPatch, "/songs/1", models.RoleEditor},
+		{http.MethodDelete, "/songs/1", models.RoleAdmin},
+		{http.MethodGet, "/admin/keys", models.RoleAdmin},
+		{http.MethodPost, "/admin/keys", models.RoleAdmin},
+		{http.MethodGet, "/administrators", models.RoleReader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if got := RequiredRole(r); got != tt.want {
+				t.Fatalf("RequiredRole() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	verifier := newTestVerifier(t, VerifierConfig{HS256Secret: testSecret, Issuer: testIssuer, Audience: testAudience})
+	token := func(role string) string {
+		return signToken(t, map[string]interface{}{"alg": "HS256"},
+			claims(map[string]interface{}{"role": role}), hs256([]byte(testSecret)))
+	}
+	keys := stubKeys{
+		"reader-key": {Subject: "reader", Role: models.RoleReader, Method: MethodAPIKey, KeyID: 1},
+		"editor-key": {Subject: "editor", Role: models.RoleEditor, Method: MethodAPIKey, KeyID: 2},
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	enabled := Config{Enabled: true, BootstrapAdminKey: "bootstrap", Public: []string{"/healthz", "/docs/swagger/"}}
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		method      string
+		path        string
+		header      string
+		value       string
+		wantStatus  int
+		wantSubject string
+	}{
+		{name: "disabled", cfg: Config{}, method: http.MethodDelete, path: "/songs/1", wantStatus: http.StatusOK},
+		{name: "public path", cfg: enabled, method: http.MethodGet, path: "/healthz", wantStatus: http.StatusOK},
+		{name: "public prefix", cfg: enabled, method: http.MethodGet, path: "/docs/swagger/index.html", wantStatus: http.StatusOK},
+		{name: "no credentials", cfg: enabled, method: http.MethodGet, path: "/songs", wantStatus: http.StatusUnauthorized},
+		{name: "other scheme", cfg: enabled, method: http.MethodGet, path: "/songs", header: "Authorization", value: "Basic cmVhZGVyLWtleQ==", wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", cfg: enabled, method: http.MethodGet, path: "/songs", header: "X-API-Key", value: "unknown", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", cfg: enabled, method: http.MethodGet, path: "/songs", header: "Authorization", value: "Bearer a.b.c", wantStatus: http.StatusUnauthorized},
+		{name: "reader key reads", cfg: enabled, method: http.MethodGet, path: "/songs", header: "X-API-Key", value: "reader-key", wantStatus: http.StatusOK, wantSubject: "reader"},
+		{name: "reader key as bearer", cfg: enabled, method: http.MethodGet, path: "/songs", header: "Authorization", value: "bearer reader-key", wantStatus: http.StatusOK, wantSubject: "reader"},
+		{name: "reader key writes", cfg: enabled, method: http.MethodPost, path: "/songs", header: "X-API-Key", value: "reader-key", wantStatus: http.StatusForbidden},
+		{name: "editor key writes", cfg: enabled, method: http.MethodPatch, path: "/songs/1", header: "X-API-Key", value: "editor-key", wantStatus: http.StatusOK, wantSubject: "editor"},
+		{name: "editor key deletes", cfg: enabled, method: http.MethodDelete, path: "/songs/1", header: "X-API-Key", value: "editor-key", wantStatus: http.StatusForbidden},
+		{name: "editor key on admin path", cfg: enabled, method: http.MethodGet, path: "/admin/keys", header: "X-API-Key", value: "editor-key", wantStatus: http.StatusForbidden},
+		{name: "bootstrap key on admin path", cfg: enabled, method: http.MethodPost, path: "/admin/keys", header: "X-API-Key", value: "bootstrap", wantStatus: http.StatusOK, wantSubject: "bootstrap"},
+		{name: "reader token reads", cfg: enabled, method: http.MethodGet, path: "/songs", header: "Authorization", value: "Bearer " + token(models.RoleReader), wantStatus: http.StatusOK, wantSubject: "client"},
+		{name: "reader token writes", cfg: enabled, method: http.MethodPut, path: "/songs/1", header: "Authorization", value: "Bearer " + token(models.RoleReader), wantStatus: http.StatusForbidden},
+		{name: "admin token deletes", cfg: enabled, method: http.MethodDelete, path: "/songs/1", header: "Authorization", value: "Bearer " + token(models.RoleAdmin), wantStatus: http.StatusOK, wantSubject: "client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if principal, ok := FromContext(r.Context()); ok {
+					subject = principal.Subject
+				}
+			})
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			New(keys, verifier, tt.cfg, logger).Middleware(next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if subject != tt.wantSubject {
+				t.Fatalf("principal subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("401 response without WWW-Authenticate header")
+			}
+		})
+	}
+}
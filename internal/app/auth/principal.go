@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+// Authentication methods of principal
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// roleLevels orders roles, role is allowed to do everything lower roles are
+var roleLevels = map[string]int{
+	models.RoleReader: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+}
+
+// Principal represents authenticated client
+// Subject is name of API key or subject of token, KeyID is set for keys stored in database
+type Principal struct {
+	Subject string
+	Role    string
+	Method  string
+	KeyID   int
+}
+
+// Allows reports whether principal has role or higher one
+func (p Principal) Allows(role string) bool {
+	return roleLevels[p.Role] >= roleLevels[role]
+}
+
+// ValidRole reports whether role is known
+func ValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// RequiredRole returns role required for request
+// Admin endpoints and deletion require admin, reading requires reader and any other change requires editor
+func RequiredRole(r *http.Request) string {
+	if strings.HasPrefix(r.URL.Path, "/admin/") {
+		return models.RoleAdmin
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.RoleReader
+	case http.MethodDelete:
+		return models.RoleAdmin
+	default:
+		return models.RoleEditor
+	}
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns copy of context carrying principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns principal of request, ok is false for unauthenticated requests
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,212 @@
+package chordpro
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+var ErrKeyRequired = errors.New("key is required for nashville notation")
+
+// chordRegexp matches chord in latin notation: root, quality and optional bass note, like "C#m7" or "G/B"
+var chordRegexp = regexp.MustCompile(`^([A-G])([#b]?)((?:maj|min|dim|aug|sus|add|alt|m|M|[0-9]|[+\-#b°øΔ^()])*)(?:/([A-G])([#b]?))?$`)
+
+// semitones maps natural notes to their pitch classes
+var semitones = map[string]int{"C": 0, "D": 2, "E": 4, "F": 5, "G": 7, "A": 9, "B": 11}
+
+// Names of pitch classes spelled with sharps and with flats
+var (
+	sharpNames = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flatNames  = [12]string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+	// In german notation B is H and Bb is B, accidentals are suffixes -is and -es
+	germanSharpNames = [12]string{"C", "Cis", "D", "Dis", "E", "F", "Fis", "G", "Gis", "A", "Ais", "H"}
+	germanFlatNames  = [12]string{"C", "Des", "D", "Es", "E", "F", "Ges", "G", "As", "A", "B", "H"}
+	// Nashville numbers are scale degrees relative to key
+	nashvilleDegrees = [12]string{"1", "b2", "2", "b3", "3", "4", "b5", "5", "b6", "6", "b7", "7"}
+)
+
+// keyAccidentals tells whether key is conventionally spelled with flats (true) or sharps (false)
+// Keys without accidentals, C and Am, are missing, so chords keep their own spelling in them
+var keyAccidentals = map[string]bool{
+	"F": true, "Bb": true, "Eb": true, "Ab": true, "Db": true, "Gb": true,
+	"Dm": true, "Gm": true, "Cm": true, "Fm": true, "Bbm": true, "Ebm": true,
+	"G": false, "D": false, "A": false, "E": false, "B": false, "F#": false,
+	"Em": false, "Bm": false, "F#m": false, "C#m": false, "G#m": false, "D#m": false,
+}
+
+// Chord represents parsed chord, Bass is -1 for chords without bass note
+// Flat records whether chord was spelled with flats, which is kept when there is no key to follow
+type Chord struct {
+	Root    int
+	Quality string
+	Bass    int
+	Flat    bool
+}
+
+// ParseChord parses chord in latin notation
+func ParseChord(name string) (Chord, bool) {
+	match := chordRegexp.FindStringSubmatch(name)
+	if match == nil {
+		return Chord{}, false
+	}
+
+	chord := Chord{Root: pitch(match[1], match[2]), Quality: match[3], Bass: -1, Flat: match[2] == "b"}
+	if match[4] != "" {
+		chord.Bass = pitch(match[4], match[5])
+		chord.Flat = chord.Flat || match[5] == "b"
+	}
+	return chord, true
+}
+
+// pitch returns pitch class of note with accidental
+func pitch(note, accidental string) int {
+	p := semitones[note]
+	switch accidental {
+	case "#":
+		p++
+	case "b":
+		p--
+	}
+	return (p + 12) % 12
+}
+
+// Transpose shifts chord by given number of semitones
+func (c Chord) Transpose(semitones int) Chord {
+	c.Root = (c.Root + semitones%12 + 12) % 12
+	if c.Bass >= 0 {
+		c.Bass = (c.Bass + semitones%12 + 12) % 12
+	}
+	return c
+}
+
+// Name renders chord in notation, key is needed for nashville notation only
+func (c Chord) Name(notation string, key Chord) string {
+	name := c.note(c.Root, notation, key) + c.Quality
+	if c.Bass >= 0 {
+		name += "/" + c.note(c.Bass, notation, key)
+	}
+	return name
+}
+
+// note renders pitch class of chord root or bass in notation
+func (c Chord) note(p int, notation string, key Chord) string {
+	switch notation {
+	case models.NotationGerman:
+		if c.Flat {
+			return germanFlatNames[p]
+		}
+		return germanSharpNames[p]
+	case models.NotationNashville:
+		return nashvilleDegrees[(p-key.Root+12)%12]
+	default:
+		if c.Flat {
+			return flatNames[p]
+		}
+		return sharpNames[p]
+	}
+}
+
+// keyFlat reports whether key is spelled with flats and whether it has accidentals at all
+func keyFlat(key Chord) (bool, bool) {
+	if flat, ok := keyAccidentals[flatNames[key.Root]+key.Quality]; ok && flat {
+		return true, true
+	}
+	flat, ok := keyAccidentals[sharpNames[key.Root]+key.Quality]
+	return flat, ok
+}
+
+// spellFor makes chord follow accidentals of key, if key is known and has them
+func (c Chord) spellFor(key *Chord) Chord {
+	if key == nil {
+		return c
+	}
+	if flat, ok := keyFlat(*key); ok {
+		c.Flat = flat
+	}
+	return c
+}
+
+// Render transposes chords of sheet and renders them in notation from options
+// Key of sheet is taken from {key} directive. Chords follow accidentals of transposed key,
+// or keep their own without key or in key without accidentals.
+// Nashville notation requires key, otherwise ErrKeyRequired is returned
+func Render(sheet models.ChordSheet, options models.ChordOptions) (models.ChordSheet, error) {
+	if options.Notation == "" {
+		options.Notation = models.NotationLatin
+	}
+
+	var key *Chord
+	if sheet.Key != "" {
+		if parsed, ok := ParseChord(sheet.Key); ok {
+			parsed = parsed.Transpose(options.Transpose)
+			parsed = parsed.spellFor(&parsed)
+			key = &parsed
+		}
+	}
+	if key == nil && options.Notation == models.NotationNashville {
+		return models.ChordSheet{}, ErrKeyRequired
+	}
+
+	var tonic Chord
+	if key != nil {
+		tonic = *key
+	}
+	name := func(chord Chord) string {
+		return chord.Transpose(options.Transpose).spellFor(key).Name(options.Notation, tonic)
+	}
+
+	result := sheet
+	result.Transpose = options.Transpose
+	result.Notation = options.Notation
+	result.Lines = make([]models.ChordLine, len(sheet.Lines))
+	if key != nil {
+		// Key itself is named in latin or german notation, nashville chart needs actual key
+		keyNotation := options.Notation
+		if keyNotation == models.NotationNashville {
+			keyNotation = models.NotationLatin
+		}
+		result.Key = key.Name(keyNotation, tonic)
+		result.Metadata = copyMetadata(sheet.Metadata)
+		result.Metadata["key"] = result.Key
+	}
+
+	for i, line := range sheet.Lines {
+		if line.Kind == models.ChordLineDirective && key != nil && isKeyDirective(line.Directive) {
+			line.Text = result.Key
+		}
+		if len(line.Chords) > 0 {
+			chords := make([]models.ChordAnchor, len(line.Chords))
+			for j, anchor := range line.Chords {
+				if chord, ok := ParseChord(anchor.Chord); ok {
+					anchor.Chord = name(chord)
+				}
+				chords[j] = anchor
+			}
+			line.Chords = chords
+		}
+		result.Lines[i] = line
+	}
+	return result, nil
+}
+
+// ParseTranspose parses number of semitones like "+2", "-3" or "5"
+// Plus sign of unescaped query string is decoded as space, so spaces are ignored
+func ParseTranspose(value string) (int, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// copyMetadata returns copy of metadata map, which is never nil
+func copyMetadata(metadata map[string]string) map[string]string {
+	result := make(map[string]string, len(metadata)+1)
+	for name, value := range metadata {
+		result[name] = value
+	}
+	return result
+}
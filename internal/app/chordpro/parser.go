@@ -0,0 +1,271 @@
+package chordpro
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"rest-songs/internal/app/lyrics"
+	"rest-songs/internal/app/models"
+)
+
+// metadataNames maps metadata directives and their short forms to names of metadata
+var metadataNames = map[string]string{
+	"title":     "title",
+	"t":         "title",
+	"subtitle":  "subtitle",
+	"st":        "subtitle",
+	"artist":    "artist",
+	"composer":  "composer",
+	"lyricist":  "lyricist",
+	"album":     "album",
+	"year":      "year",
+	"key":       "key",
+	"capo":      "capo",
+	"tempo":     "tempo",
+	"time":      "time",
+	"duration":  "duration",
+	"copyright": "copyright",
+}
+
+// sectionShortcuts maps short forms of section directives to their full forms
+var sectionShortcuts = map[string]string{
+	"soc": "start_of_chorus",
+	"eoc": "end_of_chorus",
+	"sov": "start_of_verse",
+	"eov": "end_of_verse",
+	"sob": "start_of_bridge",
+	"eob": "end_of_bridge",
+	"sot": "start_of_tab",
+	"eot": "end_of_tab",
+	"sog": "start_of_grid",
+	"eog": "end_of_grid",
+}
+
+// literalSections hold text that is not lyrics, so brackets in them are not chords
+var literalSections = map[string]bool{"tab": true, "grid": true}
+
+// SyntaxError represents error in ChordPro document with number of line it was found on
+type SyntaxError struct {
+	Line   int
+	Reason string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("строка %d: %s", e.Line, e.Reason)
+}
+
+// SyntaxErrors holds all errors found in ChordPro document
+type SyntaxErrors []SyntaxError
+
+func (e SyntaxErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Parse parses ChordPro document into lines with chords anchored to characters of lyrics
+// Document is checked as a whole, so all errors are returned at once as SyntaxErrors
+func Parse(document string) (models.ChordSheet, error) {
+	sheet := models.ChordSheet{Metadata: map[string]string{}, Lines: []models.ChordLine{}}
+	var errs SyntaxErrors
+	section, sectionLine := "", 0
+
+	// Lines are numbered as in the whole document, empty lines around it are only left out of sheet
+	texts := strings.Split(lyrics.Normalize(document), "\n")
+	first, last := 0, len(texts)
+	for first < last && texts[first] == "" {
+		first++
+	}
+	for last > first && texts[last-1] == "" {
+		last--
+	}
+
+	for i := first; i < last; i++ {
+		text := texts[i]
+		line := models.ChordLine{Number: i + 1, Section: section, Text: text}
+		trimmed := strings.TrimSpace(text)
+
+		switch {
+		case trimmed == "":
+			line.Kind = models.ChordLineEmpty
+			line.Text = ""
+		case strings.HasPrefix(trimmed, "#"):
+			line.Kind = models.ChordLineComment
+			line.Text = strings.TrimPrefix(trimmed, "#")
+		case strings.HasPrefix(trimmed, "{"):
+			if !strings.HasSuffix(trimmed, "}") {
+				errs = append(errs, SyntaxError{Line: line.Number, Reason: "директива не закрыта символом }"})
+				continue
+			}
+			line.Kind = models.ChordLineDirective
+			line.Directive, line.Text = splitDirective(trimmed[1 : len(trimmed)-1])
+			if line.Directive == "" {
+				errs = append(errs, SyntaxError{Line: line.Number, Reason: "пустое имя директивы"})
+				continue
+			}
+
+			if name, ok := metadataNames[line.Directive]; ok {
+				if name == "key" {
+					if _, ok = ParseChord(line.Text); !ok {
+						errs = append(errs, SyntaxError{Line: line.Number, Reason: fmt.Sprintf("неизвестная тональность %q", line.Text)})
+						continue
+					}
+					sheet.Key = line.Text
+				}
+				sheet.Metadata[name] = line.Text
+			} else if line.Directive == "meta" {
+				if name, value := splitDirective(line.Text); name != "" {
+					sheet.Metadata[name] = value
+				}
+			} else if name, ok := strings.CutPrefix(line.Directive, "start_of_"); ok {
+				if section != "" {
+					errs = append(errs, SyntaxError{
+						Line:   line.Number,
+						Reason: fmt.Sprintf("секция %s начата внутри секции %s из строки %d", name, section, sectionLine),
+					})
+				}
+				section, sectionLine = name, line.Number
+				line.Section = section
+			} else if name, ok = strings.CutPrefix(line.Directive, "end_of_"); ok {
+				if section != name {
+					errs = append(errs, SyntaxError{Line: line.Number, Reason: fmt.Sprintf("секция %s не была начата", name)})
+				}
+				section = ""
+			}
+		case literalSections[section]:
+			line.Kind = models.ChordLineLyrics
+		default:
+			line.Kind = models.ChordLineLyrics
+			var err error
+			line.Text, line.Chords, err = parseLyrics(text)
+			if err != nil {
+				errs = append(errs, SyntaxError{Line: line.Number, Reason: err.Error()})
+				continue
+			}
+		}
+
+		sheet.Lines = append(sheet.Lines, line)
+	}
+
+	if section != "" {
+		errs = append(errs, SyntaxError{Line: sectionLine, Reason: fmt.Sprintf("секция %s не закрыта", section)})
+	}
+	if len(errs) > 0 {
+		return models.ChordSheet{}, errs
+	}
+	return sheet, nil
+}
+
+// splitDirective splits directive into lowercase name and value separated by colon or space
+// Short forms of section directives are expanded
+func splitDirective(directive string) (string, string) {
+	directive = strings.TrimSpace(directive)
+	end := strings.IndexAny(directive, ": \t")
+	if end < 0 {
+		end = len(directive)
+	}
+
+	name := strings.ToLower(directive[:end])
+	if full, ok := sectionShortcuts[name]; ok {
+		name = full
+	}
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(directive[end:]), ":"))
+	return name, value
+}
+
+// parseLyrics extracts chords in square brackets from line of lyrics
+// Position of chord is index of character (not byte) of text without chords it precedes.
+// Annotations like [*Riff] and chords that are not recognized as no chord, like [N.C.], are kept as is
+func parseLyrics(line string) (string, []models.ChordAnchor, error) {
+	var text strings.Builder
+	var chords []models.ChordAnchor
+	position := 0
+
+	for rest := line; rest != ""; {
+		open := strings.IndexAny(rest, "[]")
+		if open < 0 {
+			text.WriteString(rest)
+			break
+		}
+		if rest[open] == ']' {
+			return "", nil, fmt.Errorf("символ ] без открывающей [")
+		}
+
+		text.WriteString(rest[:open])
+		position += len([]rune(rest[:open]))
+		rest = rest[open+1:]
+
+		end := strings.IndexAny(rest, "[]")
+		if end < 0 || rest[end] == '[' {
+			return "", nil, fmt.Errorf("аккорд не закрыт символом ]")
+		}
+		chord := strings.TrimSpace(rest[:end])
+		rest = rest[end+1:]
+
+		if chord == "" {
+			return "", nil, fmt.Errorf("пустой аккорд")
+		}
+		if _, ok := ParseChord(chord); !ok && !isAnnotation(chord) {
+			return "", nil, fmt.Errorf("неизвестный аккорд %q", chord)
+		}
+		chords = append(chords, models.ChordAnchor{Position: position, Chord: chord})
+	}
+
+	return text.String(), chords, nil
+}
+
+// isAnnotation reports whether bracketed text is annotation or no chord mark rather than chord
+func isAnnotation(chord string) bool {
+	switch strings.ToUpper(chord) {
+	case "N.C.", "NC", "N.C", "X":
+		return true
+	}
+	return strings.HasPrefix(chord, "*")
+}
+
+// isKeyDirective reports whether directive sets key of song
+func isKeyDirective(directive string) bool {
+	return metadataNames[directive] == "key"
+}
+
+// Format writes chord sheet as ChordPro document
+func Format(w io.Writer, sheet models.ChordSheet) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range sheet.Lines {
+		switch line.Kind {
+		case models.ChordLineComment:
+			bw.WriteString("#" + line.Text)
+		case models.ChordLineDirective:
+			bw.WriteString("{" + line.Directive)
+			if line.Text != "" {
+				bw.WriteString(": " + line.Text)
+			}
+			bw.WriteString("}")
+		case models.ChordLineLyrics:
+			bw.WriteString(formatLyrics(line))
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// formatLyrics inserts chords in square brackets into text of line at their positions
+func formatLyrics(line models.ChordLine) string {
+	var b strings.Builder
+	chords := line.Chords
+	for i, r := range []rune(line.Text) {
+		for len(chords) > 0 && chords[0].Position <= i {
+			b.WriteString("[" + chords[0].Chord + "]")
+			chords = chords[1:]
+		}
+		b.WriteRune(r)
+	}
+	for _, chord := range chords {
+		b.WriteString("[" + chord.Chord + "]")
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package chordpro
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rest-songs/internal/app/models"
+)
+
+func TestParse(t *testing.T) {
+	document := strings.Join([]string{
+		"",
+		"{title: Believer}",
+		"{t:Believer}",
+		"{Artist: Imagine Dragons}",
+		"{key: Bbm}",
+		"{meta: arranger Someone}",
+		"# comment",
+		"",
+		"{soc}",
+		"[Bbm]First things [Gb]first",
+		"{eoc}",
+		"{start_of_tab}",
+		"e|--[0]--|",
+		"{end_of_tab}",
+		"[*Riff]Пой [N.C.]тихо[Db/F]",
+		"",
+	}, "\r\n")
+
+	sheet, err := Parse(document)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	wantMetadata := map[string]string{"title": "Believer", "artist": "Imagine Dragons", "key": "Bbm", "arranger": "Someone"}
+	if !reflect.DeepEqual(sheet.Metadata, wantMetadata) {
+		t.Fatalf("Parse() metadata = %v, want %v", sheet.Metadata, wantMetadata)
+	}
+	if sheet.Key != "Bbm" {
+		t.Fatalf("Parse() key = %q, want Bbm", sheet.Key)
+	}
+
+	wantLines := []models.ChordLine{
+		{Number: 2, Kind: models.ChordLineDirective, Directive: "title", Text: "Believer"},
+		{Number: 3, Kind: models.ChordLineDirective, Directive: "t", Text: "Believer"},
+		{Number: 4, Kind: models.ChordLineDirective, Directive: "artist", Text: "Imagine Dragons"},
+		{Number: 5, Kind: models.ChordLineDirective, Directive: "key", Text: "Bbm"},
+		{Number: 6, Kind: models.ChordLineDirective, Directive: "meta", Text: "arranger Someone"},
+		{Number: 7, Kind: models.ChordLineComment, Text: " comment"},
+		{Number: 8, Kind: models.ChordLineEmpty},
+		{Number: 9, Kind: models.ChordLineDirective, Section: "chorus", Directive: "start_of_chorus"},
+		{
+			Number: 10, Kind: models.ChordLineLyrics, Section: "chorus", Text: "First things first",
+			Chords: []models.ChordAnchor{{Position: 0, Chord: "Bbm"}, {Position: 13, Chord: "Gb"}},
+		},
+		{Number: 11, Kind: models.ChordLineDirective, Section: "chorus", Directive: "end_of_chorus"},
+		{Number: 12, Kind: models.ChordLineDirective, Section: "tab", Directive: "start_of_tab"},
+		{Number: 13, Kind: models.ChordLineLyrics, Section: "tab", Text: "e|--[0]--|"},
+		{Number: 14, Kind: models.ChordLineDirective, Section: "tab", Directive: "end_of_tab"},
+		{
+			Number: 15, Kind: models.ChordLineLyrics, Text: "Пой тихо",
+			Chords: []models.ChordAnchor{{Position: 0, Chord: "*Riff"}, {Position: 4, Chord: "N.C."}, {Position: 8, Chord: "Db/F"}},
+		},
+	}
+	if !reflect.DeepEqual(sheet.Lines, wantLines) {
+		t.Fatalf("Parse() lines = %+v, want %+v", sheet.Lines, wantLines)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []int
+	}{
+		{name: "unclosed directive", document: "{title: Believer", want: []int{1}},
+		{name: "empty directive", document: "{ }", want: []int{1}},
+		{name: "unknown key", document: "{key: H}", want: []int{1}},
+		{name: "unknown chord", document: "[Xyz]a", want: []int{1}},
+		{name: "empty chord", document: "[ ]a", want: []int{1}},
+		{name: "unclosed chord", document: "[Am a", want: []int{1}},
+		{name: "nested chord", document: "[Am[C]] a", want: []int{1}},
+		{name: "closing bracket only", document: "a] b", want: []int{1}},
+		{name: "unclosed section", document: "a\n{soc}\n[Am]b", want: []int{2}},
+		{name: "section inside section", document: "{sov}\n{soc}\n{eoc}", want: []int{2}},
+		{name: "end of other section", document: "{sov}\na\n{eoc}", want: []int{3}},
+		{name: "end without start", document: "{end_of_bridge}", want: []int{1}},
+		{name: "all errors", document: "\n[Xyz]a\nb\n{key: Q}\n[Am", want: []int{2, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.document)
+			var errs SyntaxErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Parse() error = %v, want SyntaxErrors", err)
+			}
+
+			var lines []int
+			for _, e := range errs {
+				lines = append(lines, e.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Fatalf("Parse() errors on lines %v, want %v: %v", lines, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{name: "lyrics", document: "[Am]Пой [C]тихо", want: "[Am]Пой [C]тихо\n"},
+		{name: "chord at the end", document: "a[G]\n[C]", want: "a[G]\n[C]\n"},
+		{name: "directives", document: "{Title:Believer}\n{soc}\n{eoc}", want: "{title: Believer}\n{start_of_chorus}\n{end_of_chorus}\n"},
+		{name: "comment and empty line", document: "#note\n\n a", want: "#note\n\n a\n"},
+		{name: "tab", document: "{sot}\ne|--[0]--|\n{eot}", want: "{start_of_tab}\ne|--[0]--|\n{end_of_tab}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, err := Parse(tt.document)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var b strings.Builder
+			if err = Format(&b, sheet); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Fatalf("Format() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
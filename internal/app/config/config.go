@@ -3,15 +3,121 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
-var defaultHttpPort = ":8080"
+var (
+	defaultHttpPort = ":8080"
+
+	defaultHttpReadHeaderTimeout = 5 * time.Second
+	defaultHttpReadTimeout       = time.Minute
+	defaultHttpWriteTimeout      = time.Minute
+	defaultHttpBulkTimeout       = 30 * time.Minute
+	defaultHttpIdleTimeout       = 2 * time.Minute
+	defaultHttpMaxHeaderBytes    = 64 << 10
+	defaultShutdownTimeout       = 30 * time.Second
+	defaultShutdownDrainDelay    = time.Duration(0)
+
+	defaultDbReadTimeout  = 5 * time.Second
+	defaultDbWriteTimeout = 10 * time.Second
+	defaultDbBulkTimeout  = 10 * time.Minute
+
+	defaultExternalAPITimeout          = 5 * time.Second
+	defaultExternalAPIMaxRetries       = 2
+	defaultExternalAPIRetryBackoff     = 200 * time.Millisecond
+	defaultExternalAPIMaxRetryBackoff  = 5 * time.Second
+	defaultExternalAPIBreakerThreshold = 5
+	defaultExternalAPIBreakerCooldown  = 30 * time.Second
+
+	defaultEnrichmentWorkers         = 2
+	defaultEnrichmentPollInterval    = time.Second
+	defaultEnrichmentLease           = time.Minute
+	defaultEnrichmentMaxAttempts     = 5
+	defaultEnrichmentRetryBackoff    = 10 * time.Second
+	defaultEnrichmentMaxRetryBackoff = 10 * time.Minute
+
+	defaultTrashRetentionDays = 30
+	defaultTrashPurgeInterval = time.Hour
+
+	defaultAuthEnabled = true
+
+	defaultRateLimitEnabled           = true
+	defaultRateLimitReadPerMinute     = 600
+	defaultRateLimitReadBurst         = 60
+	defaultRateLimitWritePerMinute    = 120
+	defaultRateLimitWriteBurst        = 20
+	defaultRateLimitUpstreamPerMinute = 30
+	defaultRateLimitUpstreamBurst     = 5
+	defaultRateLimitUpstreamDaily     = 1000
+	defaultRateLimitIPPerMinute       = 1200
+	defaultRateLimitIPBurst           = 120
+
+	defaultHealthCheckTimeout = 2 * time.Second
+	defaultHealthMusicInfo    = false
+	defaultHealthMusicInfoTTL = 30 * time.Second
+)
 
 // Config struct holds configuration values for database url, http port and external api url
 type Config struct {
 	DbUrl       string
 	HttpPort    string
 	ExternalAPI string
+
+	HttpReadHeaderTimeout time.Duration
+	HttpReadTimeout       time.Duration
+	HttpWriteTimeout      time.Duration
+	HttpBulkTimeout       time.Duration
+	HttpIdleTimeout       time.Duration
+	HttpMaxHeaderBytes    int
+	ShutdownTimeout       time.Duration
+	ShutdownDrainDelay    time.Duration
+
+	DbReadTimeout  time.Duration
+	DbWriteTimeout time.Duration
+	DbBulkTimeout  time.Duration
+
+	ExternalAPITimeout          time.Duration
+	ExternalAPIMaxRetries       int
+	ExternalAPIRetryBackoff     time.Duration
+	ExternalAPIMaxRetryBackoff  time.Duration
+	ExternalAPIBreakerThreshold int
+	ExternalAPIBreakerCooldown  time.Duration
+
+	EnrichmentWorkers         int
+	EnrichmentPollInterval    time.Duration
+	EnrichmentLease           time.Duration
+	EnrichmentMaxAttempts     int
+	EnrichmentRetryBackoff    time.Duration
+	EnrichmentMaxRetryBackoff time.Duration
+
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	AuthEnabled           bool
+	AuthBootstrapAdminKey string
+	JWTSecret             string
+	JWTKeysFile           string
+	JWTIssuer             string
+	JWTAudience           string
+
+	RateLimitEnabled  bool
+	RateLimitRead     RateLimit
+	RateLimitWrite    RateLimit
+	RateLimitUpstream RateLimit
+	RateLimitIP       RateLimit
+
+	HealthCheckTimeout time.Duration
+	HealthMusicInfo    bool
+	HealthMusicInfoTTL time.Duration
+}
+
+// RateLimit holds limits of request class: requests per minute, burst and daily quota
+// Zero PerMinute or Daily disables that limit
+type RateLimit struct {
+	PerMinute int
+	Burst     int
+	Daily     int
 }
 
 // New creates new Config instance by reading environment variables
@@ -33,9 +139,198 @@ func New() (*Config, error) {
 		return nil, fmt.Errorf("externalAPI не задан")
 	}
 
-	return &Config{
+	cfg := &Config{
 		DbUrl:       dbURL,
 		HttpPort:    httpPort,
 		ExternalAPI: externalAPI,
-	}, nil
+	}
+
+	var err error
+	if cfg.HttpReadHeaderTimeout, err = getDuration("HTTP_READ_HEADER_TIMEOUT", defaultHttpReadHeaderTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.HttpReadTimeout, err = getDuration("HTTP_READ_TIMEOUT", defaultHttpReadTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.HttpWriteTimeout, err = getDuration("HTTP_WRITE_TIMEOUT", defaultHttpWriteTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.HttpBulkTimeout, err = getDuration("HTTP_BULK_TIMEOUT", defaultHttpBulkTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.HttpIdleTimeout, err = getDuration("HTTP_IDLE_TIMEOUT", defaultHttpIdleTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.HttpMaxHeaderBytes, err = getInt("HTTP_MAX_HEADER_BYTES", defaultHttpMaxHeaderBytes); err != nil {
+		return nil, err
+	}
+	if cfg.ShutdownTimeout, err = getDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.ShutdownDrainDelay, err = getDuration("SHUTDOWN_DRAIN_DELAY", defaultShutdownDrainDelay); err != nil {
+		return nil, err
+	}
+
+	if cfg.DbReadTimeout, err = getDuration("DB_READ_TIMEOUT", defaultDbReadTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.DbWriteTimeout, err = getDuration("DB_WRITE_TIMEOUT", defaultDbWriteTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.DbBulkTimeout, err = getDuration("DB_BULK_TIMEOUT", defaultDbBulkTimeout); err != nil {
+		return nil, err
+	}
+
+	if cfg.ExternalAPITimeout, err = getDuration("EXTERNAL_API_TIMEOUT", defaultExternalAPITimeout); err != nil {
+		return nil, err
+	}
+	if cfg.ExternalAPIMaxRetries, err = getInt("EXTERNAL_API_MAX_RETRIES", defaultExternalAPIMaxRetries); err != nil {
+		return nil, err
+	}
+	if cfg.ExternalAPIRetryBackoff, err = getDuration("EXTERNAL_API_RETRY_BACKOFF", defaultExternalAPIRetryBackoff); err != nil {
+		return nil, err
+	}
+	if cfg.ExternalAPIMaxRetryBackoff, err = getDuration("EXTERNAL_API_MAX_RETRY_BACKOFF", defaultExternalAPIMaxRetryBackoff); err != nil {
+		return nil, err
+	}
+	if cfg.ExternalAPIBreakerThreshold, err = getInt("EXTERNAL_API_BREAKER_THRESHOLD", defaultExternalAPIBreakerThreshold); err != nil {
+		return nil, err
+	}
+	if cfg.ExternalAPIBreakerCooldown, err = getDuration("EXTERNAL_API_BREAKER_COOLDOWN", defaultExternalAPIBreakerCooldown); err != nil {
+		return nil, err
+	}
+
+	if cfg.EnrichmentWorkers, err = getInt("ENRICHMENT_WORKERS", defaultEnrichmentWorkers); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichmentPollInterval, err = getDuration("ENRICHMENT_POLL_INTERVAL", defaultEnrichmentPollInterval); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichmentLease, err = getDuration("ENRICHMENT_LEASE", defaultEnrichmentLease); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichmentMaxAttempts, err = getInt("ENRICHMENT_MAX_ATTEMPTS", defaultEnrichmentMaxAttempts); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichmentRetryBackoff, err = getDuration("ENRICHMENT_RETRY_BACKOFF", defaultEnrichmentRetryBackoff); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichmentMaxRetryBackoff, err = getDuration("ENRICHMENT_MAX_RETRY_BACKOFF", defaultEnrichmentMaxRetryBackoff); err != nil {
+		return nil, err
+	}
+
+	retentionDays, err := getInt("TRASH_RETENTION_DAYS", defaultTrashRetentionDays)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TrashRetention = time.Duration(retentionDays) * 24 * time.Hour
+	if cfg.TrashPurgeInterval, err = getDuration("TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval); err != nil {
+		return nil, err
+	}
+
+	if cfg.AuthEnabled, err = getBool("AUTH_ENABLED", defaultAuthEnabled); err != nil {
+		return nil, err
+	}
+	cfg.AuthBootstrapAdminKey = os.Getenv("AUTH_BOOTSTRAP_ADMIN_KEY")
+	cfg.JWTSecret = os.Getenv("JWT_HS256_SECRET")
+	cfg.JWTKeysFile = os.Getenv("JWT_KEYS_FILE")
+	cfg.JWTIssuer = os.Getenv("JWT_ISSUER")
+	cfg.JWTAudience = os.Getenv("JWT_AUDIENCE")
+
+	if cfg.RateLimitEnabled, err = getBool("RATE_LIMIT_ENABLED", defaultRateLimitEnabled); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitRead, err = getRateLimit("READ", RateLimit{
+		PerMinute: defaultRateLimitReadPerMinute,
+		Burst:     defaultRateLimitReadBurst,
+	}); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitWrite, err = getRateLimit("WRITE", RateLimit{
+		PerMinute: defaultRateLimitWritePerMinute,
+		Burst:     defaultRateLimitWriteBurst,
+	}); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitUpstream, err = getRateLimit("UPSTREAM", RateLimit{
+		PerMinute: defaultRateLimitUpstreamPerMinute,
+		Burst:     defaultRateLimitUpstreamBurst,
+		Daily:     defaultRateLimitUpstreamDaily,
+	}); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitIP, err = getRateLimit("IP", RateLimit{
+		PerMinute: defaultRateLimitIPPerMinute,
+		Burst:     defaultRateLimitIPBurst,
+	}); err != nil {
+		return nil, err
+	}
+
+	if cfg.HealthCheckTimeout, err = getDuration("HEALTH_CHECK_TIMEOUT", defaultHealthCheckTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.HealthMusicInfo, err = getBool("HEALTH_MUSIC_INFO", defaultHealthMusicInfo); err != nil {
+		return nil, err
+	}
+	if cfg.HealthMusicInfoTTL, err = getDuration("HEALTH_MUSIC_INFO_TTL", defaultHealthMusicInfoTTL); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// getDuration reads duration (e.g. "5s", "300ms") from environment variable or returns default value
+func getDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s имеет неправильный формат: %q", key, value)
+	}
+	return d, nil
+}
+
+// getInt reads non-negative integer from environment variable or returns default value
+func getInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s имеет неправильный формат: %q", key, value)
+	}
+	return n, nil
+}
+
+// getRateLimit reads RATE_LIMIT_<class>_PER_MINUTE, RATE_LIMIT_<class>_BURST and RATE_LIMIT_<class>_DAILY
+// from environment variables or returns default values
+func getRateLimit(class string, defaultValue RateLimit) (RateLimit, error) {
+	var limit RateLimit
+	var err error
+	if limit.PerMinute, err = getInt("RATE_LIMIT_"+class+"_PER_MINUTE", defaultValue.PerMinute); err != nil {
+		return RateLimit{}, err
+	}
+	if limit.Burst, err = getInt("RATE_LIMIT_"+class+"_BURST", defaultValue.Burst); err != nil {
+		return RateLimit{}, err
+	}
+	if limit.Daily, err = getInt("RATE_LIMIT_"+class+"_DAILY", defaultValue.Daily); err != nil {
+		return RateLimit{}, err
+	}
+	return limit, nil
+}
+
+// getBool reads boolean ("true", "false", "1", "0") from environment variable or returns default value
+func getBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s имеет неправильный формат: %q", key, value)
+	}
+	return b, nil
 }
@@ -0,0 +1,139 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/musicinfo"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// Config holds settings for enrichment worker pool
+type Config struct {
+	Workers         int
+	PollInterval    time.Duration
+	Lease           time.Duration
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// Worker fetches details of pending songs from music info service in background
+// Jobs are taken from persistent queue in database, so they survive restarts and outages of external API
+type Worker struct {
+	repo      postgresql.EnrichmentRepository
+	musicInfo musicinfo.MusicInfoProvider
+	cfg       Config
+	logger    *logrus.Logger
+}
+
+// New creates new Worker instance and takes repository, music info provider, Config and logger as parameters
+func New(repo postgresql.EnrichmentRepository, musicInfo musicinfo.MusicInfoProvider, cfg Config, logger *logrus.Logger) *Worker {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Worker{
+		repo:      repo,
+		musicInfo: musicInfo,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Run starts pool of workers and blocks until ctx is cancelled and all workers finish current jobs
+func (w *Worker) Run(ctx context.Context) {
+	w.logger.Infof("Run[enrichment]: Запуск %d обработчиков", w.cfg.Workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+
+	w.logger.Infof("Run[enrichment]: Обработчики остановлены")
+}
+
+// loop processes jobs one by one, sleeping for poll interval when queue is empty
+func (w *Worker) loop(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, err := w.repo.ClaimEnrichmentJob(ctx, w.cfg.Lease)
+		if err == nil {
+			w.process(ctx, job)
+			continue
+		}
+		if !errors.Is(err, postgresql.ErrNoJobs) {
+			w.logger.Errorf("loop[enrichment]: Ошибка получения задачи: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.cfg.PollInterval):
+		}
+	}
+}
+
+// process fetches song details for job and stores result
+// Unknown songs, requests rejected by music info service and unparsable details are not retried,
+// other failures are retried until MaxAttempts
+// Job interrupted by cancellation of ctx is left as is, so it is claimed again after its lease expires
+func (w *Worker) process(ctx context.Context, job models.EnrichmentJob) {
+	w.logger.Infof("process[enrichment]: Обработка задачи ID: %d, попытка: %d", job.ID, job.Attempts)
+
+	detail, err := w.musicInfo.GetSongDetail(ctx, job.Group, job.Title)
+	if err != nil {
+		if ctx.Err() != nil {
+			w.logger.Warnf("process[enrichment]: Обработка задачи ID %d прервана", job.ID)
+			return
+		}
+		w.fail(ctx, job, err, errors.Is(err, musicinfo.ErrNotFound) || errors.Is(err, musicinfo.ErrRejected))
+		return
+	}
+
+	releaseDate, err := time.Parse("02.01.2006", detail.ReleaseDate)
+	if err != nil {
+		w.fail(ctx, job, err, true)
+		return
+	}
+
+	if err = w.repo.CompleteEnrichmentJob(ctx, job, releaseDate, detail.Text, detail.Link); err != nil {
+		w.logger.Errorf("process[enrichment]: Ошибка сохранения деталей песни ID %d: %v", job.SongID, err)
+		return
+	}
+	w.logger.Infof("process[enrichment]: Песня ID %d успешно обогащена", job.SongID)
+}
+
+// fail records failed attempt, moving job to dead state when it is permanent or attempts are exhausted
+func (w *Worker) fail(ctx context.Context, job models.EnrichmentJob, cause error, permanent bool) {
+	dead := permanent || job.Attempts >= w.cfg.MaxAttempts
+	retryAt := time.Now().Add(w.backoff(job.Attempts))
+
+	w.logger.Warnf("fail[enrichment]: Задача ID %d завершилась ошибкой (dead: %t): %v", job.ID, dead, cause)
+	if err := w.repo.FailEnrichmentJob(ctx, job, cause.Error(), retryAt, dead); err != nil {
+		w.logger.Errorf("fail[enrichment]: Ошибка сохранения результата задачи ID %d: %v", job.ID, err)
+	}
+}
+
+// backoff returns exponential delay before next attempt, capped by MaxRetryBackoff
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.cfg.RetryBackoff << (attempts - 1)
+	if delay <= 0 || delay > w.cfg.MaxRetryBackoff {
+		delay = w.cfg.MaxRetryBackoff
+	}
+	return delay
+}
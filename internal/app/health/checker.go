@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// Names of checked dependencies in readiness report
+const (
+	CheckShutdown   = "shutdown"
+	CheckDatabase   = "database"
+	CheckMigrations = "migrations"
+	CheckMusicInfo  = "musicinfo"
+)
+
+// Prober checks availability of external service
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// Config holds settings of readiness checks
+// Music info service is probed only if MusicInfo is set, its result is cached for MusicInfoTTL.
+// Failure of music info service doesn't make service unready, since songs are enriched in background
+type Config struct {
+	ExpectedMigration int64
+	Timeout           time.Duration
+	MusicInfo         bool
+	MusicInfoTTL      time.Duration
+}
+
+// Checker reports liveness and readiness of service
+type Checker struct {
+	repo      postgresql.HealthRepository
+	musicInfo Prober
+	cfg       Config
+	logger    *logrus.Logger
+
+	shuttingDown atomic.Bool
+
+	mu             sync.Mutex
+	musicInfoCheck models.HealthCheck
+	now            func() time.Time
+}
+
+// New creates new Checker instance and takes repository, music info prober, Config and logger as parameters
+func New(repo postgresql.HealthRepository, musicInfo Prober, cfg Config, logger *logrus.Logger) *Checker {
+	return &Checker{
+		repo:      repo,
+		musicInfo: musicInfo,
+		cfg:       cfg,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Shutdown marks service as shutting down, it is reported as not ready from then on
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Live reports liveness of service, which is alive as long as it can answer
+func (c *Checker) Live() models.HealthReport {
+	return models.HealthReport{Status: models.HealthOK}
+}
+
+// Ready checks dependencies of service and reports its readiness with breakdown by dependency
+// Service is not ready if any required check fails, failed optional checks make it degraded
+func (c *Checker) Ready(ctx context.Context) models.HealthReport {
+	report := models.HealthReport{Status: models.HealthOK, Checks: make(map[string]models.HealthCheck)}
+
+	if c.shuttingDown.Load() {
+		report.Status = models.HealthFail
+		report.Checks[CheckShutdown] = models.HealthCheck{
+			Status:    models.HealthFail,
+			Required:  true,
+			Error:     "Сервис останавливается",
+			CheckedAt: c.now(),
+		}
+		return report
+	}
+
+	report.Checks[CheckDatabase] = c.checkDatabase(ctx)
+	report.Checks[CheckMigrations] = c.checkMigrations(ctx)
+	if c.cfg.MusicInfo {
+		report.Checks[CheckMusicInfo] = c.checkMusicInfo(ctx)
+	}
+
+	for name, check := range report.Checks {
+		if check.Status == models.HealthOK {
+			continue
+		}
+		c.logger.Warnf("Ready[health]: Проверка %s не пройдена: %s", name, check.Error)
+		if check.Required {
+			report.Status = models.HealthFail
+		} else if report.Status == models.HealthOK {
+			report.Status = models.HealthDegraded
+		}
+	}
+	return report
+}
+
+// checkDatabase pings connection pool
+func (c *Checker) checkDatabase(ctx context.Context) models.HealthCheck {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return result(c.now(), true, c.repo.Ping(ctx), "")
+}
+
+// checkMigrations compares schema version of database with version of the newest migration known to service
+// Newer schema is accepted, since it is expected while new version of service is being rolled out
+func (c *Checker) checkMigrations(ctx context.Context) models.HealthCheck {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	version, err := c.repo.MigrationVersion(ctx)
+	if err != nil {
+		return result(c.now(), true, err, "")
+	}
+
+	details := fmt.Sprintf("версия схемы %d, ожидается %d", version, c.cfg.ExpectedMigration)
+	if version < c.cfg.ExpectedMigration {
+		return result(c.now(), true, fmt.Errorf("миграции не применены: %s", details), "")
+	}
+	return result(c.now(), true, nil, details)
+}
+
+// checkMusicInfo probes music info service, result is reused until MusicInfoTTL passes
+// Concurrent checks wait for single probe instead of sending their own
+func (c *Checker) checkMusicInfo(ctx context.Context) models.HealthCheck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.musicInfoCheck.CheckedAt.IsZero() && c.now().Sub(c.musicInfoCheck.CheckedAt) < c.cfg.MusicInfoTTL {
+		return c.musicInfoCheck
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	err := c.musicInfo.Probe(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		// Caller gave up, so result says nothing about music info service and isn't cached
+		return result(c.now(), false, err, "")
+	}
+
+	c.musicInfoCheck = result(c.now(), false, err, "")
+	return c.musicInfoCheck
+}
+
+// withTimeout limits single check by configured timeout
+func (c *Checker) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.cfg.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.cfg.Timeout)
+}
+
+// result builds check with status derived from error
+func result(checkedAt time.Time, required bool, err error, details string) models.HealthCheck {
+	check := models.HealthCheck{Status: models.HealthOK, Required: required, Details: details, CheckedAt: checkedAt}
+	if err != nil {
+		check.Status = models.HealthFail
+		check.Error = err.Error()
+	}
+	return check
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// IssueAPIKeyHandler handles POST requests to issue new API key
+// @Summary Issue API key
+// @Description Issue API key with role reader, editor or admin. Key is returned only in this response,
+// @Description only its hash is stored. Key is passed in "Authorization: Bearer" or X-API-Key header
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param key body models.APIKeyRequest true "Name and role of key"
+// @Success 201 {object} models.IssuedAPIKey "Issued key"
+// @Failure 400 {string} string "Неправильный формат данных или Неизвестная роль"
+// @Failure 401 {string} string "Требуется аутентификация"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /admin/keys [post]
+func (h *Handler) IssueAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.APIKeyRequest
+
+	// Decode request body into input struct
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.service.IssueAPIKey(r.Context(), input)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+// GetAPIKeysHandler handles GET requests to list API keys
+// @Summary Get API keys
+// @Description Get API keys, newest first, including revoked ones. Keys themselves are not returned
+// @Tags Admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(10)
+// @Success 200 {object} models.Page[models.APIKey] "Page of keys"
+// @Header 200 {string} Link "Links to first, prev, next and last pages"
+// @Failure 401 {string} string "Требуется аутентификация"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /admin/keys [get]
+func (h *Handler) GetAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination parameters
+	page, pageSize := parsePagination(r.URL.Query())
+
+	keys, err := h.service.GetAPIKeys(r.Context(), page, pageSize)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	setPageLinks(w, r, keys)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// RevokeAPIKeyHandler handles DELETE requests to revoke API key
+// @Summary Revoke API key
+// @Tags Admin
+// @Param id path int true "Key ID"
+// @Success 204 "No Content - Successfully revoked"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 401 {string} string "Требуется аутентификация"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Ключ не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /admin/keys/{id} [delete]
+func (h *Handler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.RevokeAPIKey(r.Context(), id); err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAPIKeyError maps API key service error to HTTP response
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, api.ErrEmptyKeyName):
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+	case errors.Is(err, api.ErrInvalidRole):
+		http.Error(w, "Неизвестная роль: ожидается reader, editor или admin", http.StatusBadRequest)
+	case errors.Is(err, postgresql.ErrAPIKeyNotFound):
+		http.Error(w, "Ключ не найден", http.StatusNotFound)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,208 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// GetArtistsHandler handles GET request for retrieving list of groups
+// @Summary Get groups
+// @Description Get groups ordered by name with pagination
+// @Tags Groups
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(10)
+// @Success 200 {array} models.Artist
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /groups [get]
+func (h *Handler) GetArtistsHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination parameters
+	page, pageSize := parsePagination(r.URL.Query())
+
+	// Call service to get groups
+	artists, err := h.service.GetArtists(r.Context(), page, pageSize)
+	if err != nil {
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
+	}
+
+	// Respond with list of groups
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(artists)
+}
+
+// GetArtistByIdHandler handles GET requests to retrieve group by its ID
+// @Summary Get group by ID
+// @Tags Groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {object} models.Artist
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /groups/{id} [get]
+func (h *Handler) GetArtistByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	artist, err := h.service.GetArtistById(r.Context(), id)
+	if err != nil {
+		writeArtistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(artist)
+}
+
+// AddArtistHandler handles POST requests to add a new group
+// @Summary Add a new group
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group body models.ArtistRequest true "Group name"
+// @Success 201 {object} models.Artist "Created group"
+// @Failure 400 {string} string "Неправильный формат данных"
+// @Failure 409 {string} string "Группа уже существует"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /groups [post]
+func (h *Handler) AddArtistHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.ArtistRequest
+
+	// Decode request body into input struct
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	artist, err := h.service.CreateArtist(r.Context(), input.Name)
+	if err != nil {
+		writeArtistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(artist)
+}
+
+// UpdateArtistByIdHandler handles PUT requests to rename group by its ID
+// @Summary Rename group by ID
+// @Description Rename group, all songs of group are updated as well
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Group ID"
+// @Param group body models.ArtistRequest true "New group name"
+// @Success 200 {object} models.Artist "Updated group"
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный формат данных"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 409 {string} string "Группа уже существует"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /groups/{id} [put]
+func (h *Handler) UpdateArtistByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.ArtistRequest
+
+	// Decode request body into input struct
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	artist, err := h.service.UpdateArtist(r.Context(), id, input.Name)
+	if err != nil {
+		writeArtistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(artist)
+}
+
+// DeleteArtistByIdHandler handles DELETE requests to remove group by its ID
+// @Summary Delete group by ID
+// @Description Delete group without songs
+// @Tags Groups
+// @Param id path int true "Group ID"
+// @Success 204 "No Content - Successfully deleted"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 409 {string} string "У группы есть песни или У группы есть песни в корзине"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /groups/{id} [delete]
+func (h *Handler) DeleteArtistByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.DeleteArtist(r.Context(), id); err != nil {
+		writeArtistError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetArtistSongsHandler handles GET requests to retrieve discography of group
+// @Summary Get group discography
+// @Description Get songs of group in chronological order
+// @Tags Groups
+// @Produce json
+// @Param id path int true "Group ID"
+// @Success 200 {array} models.Song
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /groups/{id}/songs [get]
+func (h *Handler) GetArtistSongsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	songs, err := h.service.GetArtistSongs(r.Context(), id)
+	if err != nil {
+		writeArtistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(songs)
+}
+
+// writeArtistError maps artist service error to HTTP response
+func writeArtistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, api.ErrEmptyArtistName):
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+	case errors.Is(err, postgresql.ErrArtistNotFound):
+		http.Error(w, "Группа не найдена", http.StatusNotFound)
+	case errors.Is(err, postgresql.ErrArtistExists):
+		http.Error(w, "Группа уже существует", http.StatusConflict)
+	case errors.Is(err, postgresql.ErrArtistHasSongs):
+		http.Error(w, "У группы есть песни", http.StatusConflict)
+	case errors.Is(err, postgresql.ErrArtistHasDeletedSongs):
+		http.Error(w, "У группы есть песни в корзине, они будут удалены после очистки корзины", http.StatusConflict)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/chordpro"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// chordProContentType is content type of ChordPro documents
+const chordProContentType = "application/x-chordpro"
+
+// maxChordSheetBytes limits size of ChordPro document, larger documents are rejected with 413
+const maxChordSheetBytes = 1 << 20
+
+// GetSongChordsHandler handles GET requests to retrieve chord sheet of song
+// @Summary Get song chords
+// @Description Get ChordPro chord sheet of song as lines with chords anchored at character positions,
+// @Description or as ChordPro document with format=chordpro. Chords are transposed by given number of semitones
+// @Description and follow accidentals of transposed {key}. Nashville notation needs {key} in document
+// @Tags Chords
+// @Produce json
+// @Produce plain
+// @Param id path int true "Song ID"
+// @Param transpose query string false "Semitones to transpose by, from -11 to +11" default(0)
+// @Param notation query string false "Notation of chords" Enums(latin, german, nashville) default(latin)
+// @Param format query string false "Format of response" Enums(json, chordpro) default(json)
+// @Success 200 {object} models.ChordSheet
+// @Failure 400 {string} string "Неправильный формат ID или Неправильные параметры аккордов"
+// @Failure 404 {string} string "Песня не найдена или Аккорды не найдены"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/chords [get]
+func (h *Handler) GetSongChordsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "chordpro" {
+		http.Error(w, "Неправильный формат: ожидается json или chordpro", http.StatusBadRequest)
+		return
+	}
+	transpose, err := chordpro.ParseTranspose(query.Get("transpose"))
+	if err != nil {
+		http.Error(w, "Неправильный параметр transpose: ожидается число полутонов от -11 до +11", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to get chord sheet
+	sheet, err := h.service.GetSongChords(r.Context(), id, models.ChordOptions{Transpose: transpose, Notation: query.Get("notation")})
+	if err != nil {
+		writeChordsError(w, err)
+		return
+	}
+
+	if format == "chordpro" {
+		w.Header().Set("Content-Type", chordProContentType+"; charset=utf-8")
+		if err = chordpro.Format(w, sheet); err != nil {
+			h.logger.Errorf("GetSongChordsHandler[handler]: Ошибка записи ChordPro песни ID %d: %v", id, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sheet)
+}
+
+// SetSongChordsHandler handles PUT requests to set chord sheet of song
+// @Summary Set song chords
+// @Description Set ChordPro document of song. Lyrics lines carry chords in square brackets, like "[Am]Pain! [F]You made me",
+// @Description directives are in curly braces, like {key: Am} or {start_of_chorus}. Malformed document is rejected
+// @Description with all errors, one per line, each with number of line it was found on. Document is limited to 1 MiB
+// @Tags Chords
+// @Accept plain
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param body body string true "ChordPro document"
+// @Success 200 {object} models.ChordSheet
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный документ ChordPro"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 413 {string} string "Документ слишком большой"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/chords [put]
+func (h *Handler) SetSongChordsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	document, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxChordSheetBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Документ слишком большой: ожидается не больше 1 МиБ", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Не удалось прочитать тело запроса", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to set chord sheet
+	sheet, err := h.service.SetSongChords(r.Context(), id, string(document))
+	if err != nil {
+		writeChordsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sheet)
+}
+
+// DeleteSongChordsHandler handles DELETE requests to remove chord sheet of song
+// @Summary Delete song chords
+// @Tags Chords
+// @Param id path int true "Song ID"
+// @Success 204 "Аккорды удалены"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Аккорды не найдены"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/chords [delete]
+func (h *Handler) DeleteSongChordsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to delete chord sheet
+	if err = h.service.DeleteSongChords(r.Context(), id); err != nil {
+		writeChordsError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeChordsError maps error of chord sheet operations to HTTP response
+func writeChordsError(w http.ResponseWriter, err error) {
+	var syntaxErrs chordpro.SyntaxErrors
+	switch {
+	case errors.As(err, &syntaxErrs):
+		http.Error(w, "Неправильный документ ChordPro:\n"+syntaxErrs.Error(), http.StatusBadRequest)
+	case errors.Is(err, api.ErrInvalidNotation):
+		http.Error(w, "Неправильная нотация: ожидается latin, german или nashville", http.StatusBadRequest)
+	case errors.Is(err, api.ErrInvalidTranspose):
+		http.Error(w, "Неправильный параметр transpose: ожидается число полутонов от -11 до +11", http.StatusBadRequest)
+	case errors.Is(err, chordpro.ErrKeyRequired):
+		http.Error(w, "Для нотации nashville в документе нужна тональность {key}", http.StatusBadRequest)
+	case errors.Is(err, postgresql.ErrChordSheetNotFound):
+		http.Error(w, "Аккорды не найдены", http.StatusNotFound)
+	case errors.Is(err, postgresql.ErrSongNotFound):
+		http.Error(w, "Песня не найдена", http.StatusNotFound)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
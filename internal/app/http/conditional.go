@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// errPreconditionFailed is returned when If-Match header can't match any version of song
+var errPreconditionFailed = errors.New("precondition failed")
+
+// songETag returns strong entity tag of song derived from its version
+func songETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setSongValidators sets ETag and Last-Modified headers of song representation
+func setSongValidators(w http.ResponseWriter, version int, updatedAt time.Time) {
+	w.Header().Set("ETag", songETag(version))
+	w.Header().Set("Last-Modified", updatedAt.UTC().Format(http.TimeFormat))
+}
+
+// notModified reports whether conditional GET request may be answered with 304 Not Modified
+// If-None-Match takes precedence over If-Modified-Since (RFC 9110, section 13.2.2)
+func notModified(r *http.Request, version int, updatedAt time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, tag := range splitETags(header) {
+			// If-None-Match uses weak comparison
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == songETag(version) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+		// Last-Modified has precision of seconds
+		return !updatedAt.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// writeNotModified responds with 304 Not Modified keeping validators of song
+func writeNotModified(w http.ResponseWriter, version int, updatedAt time.Time) {
+	setSongValidators(w, version, updatedAt)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// expectedVersion returns version of song required by If-Match header
+// 0 means that request is unconditional. If header lists several entity tags,
+// current version of song is looked up, and errPreconditionFailed is returned if none matches
+func (h *Handler) expectedVersion(r *http.Request, id int) (int, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, nil
+	}
+
+	var versions []int
+	for _, tag := range splitETags(header) {
+		if tag == "*" {
+			return 0, nil
+		}
+		// If-Match uses strong comparison, so weak tags never match
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		version, err := strconv.Atoi(tag[1 : len(tag)-1])
+		if err != nil || version < 1 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	switch len(versions) {
+	case 0:
+		return 0, errPreconditionFailed
+	case 1:
+		return versions[0], nil
+	}
+
+	song, err := h.service.GetSongById(r.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	for _, version := range versions {
+		if version == song.Version {
+			return version, nil
+		}
+	}
+	return 0, errPreconditionFailed
+}
+
+// splitETags splits comma separated list of entity tags
+func splitETags(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// writePreconditionError responds with 412 Precondition Failed if err is caused by If-Match mismatch
+// It reports whether response was written
+func writePreconditionError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, errPreconditionFailed) || errors.Is(err, postgresql.ErrVersionMismatch) {
+		http.Error(w, "Версия песни не совпадает с If-Match", http.StatusPreconditionFailed)
+		return true
+	}
+	return false
+}
+
+// writeSong responds with song and its validators
+func writeSong(w http.ResponseWriter, status int, song models.Song) {
+	setSongValidators(w, song.Version, song.UpdatedAt)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(song)
+}
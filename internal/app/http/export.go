@@ -0,0 +1,320 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-songs/internal/app/models"
+)
+
+// exportFlushEvery is number of songs written between flushes of response to client
+const exportFlushEvery = 100
+
+// exportColumns lists columns available for export in default order
+// Release date is written in "02.01.2006" format, the same as accepted by import
+var exportColumns = []string{
+	"id", "artist_id", "group", "song", "release_date", "text", "link",
+	"enrichment_status", "version", "created_at", "updated_at",
+}
+
+// exportValue returns value of song column for export
+func exportValue(song models.Song, column string) interface{} {
+	switch column {
+	case "id":
+		return song.ID
+	case "artist_id":
+		return song.ArtistID
+	case "group":
+		return song.Group
+	case "song":
+		return song.Title
+	case "release_date":
+		if song.ReleaseDate == nil {
+			return nil
+		}
+		return song.ReleaseDate.Format("02.01.2006")
+	case "text":
+		return song.Text
+	case "link":
+		return song.Link
+	case "enrichment_status":
+		return song.EnrichmentStatus
+	case "version":
+		return song.Version
+	case "created_at":
+		return song.CreatedAt
+	case "updated_at":
+		return song.UpdatedAt
+	}
+	return nil
+}
+
+// songWriter writes exported songs in some format
+type songWriter interface {
+	write(song models.Song) error
+	close() error
+}
+
+// ExportSongsHandler handles GET requests for export of library
+// @Summary Export songs
+// @Description Export all songs matching filters as CSV, NDJSON or JSON array. Songs are streamed from database
+// @Description to response as they are read, so export of whole library doesn't need memory for all of it.
+// @Description Release date is written in DD.MM.YYYY format, so export may be loaded back by POST /songs/import
+// @Tags Songs
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Param format query string false "Export format" Enums(csv, ndjson, json) default(csv)
+// @Param columns query string false "Comma separated columns: id, artist_id, group, song, release_date, text, link, enrichment_status, version, created_at, updated_at. All by default"
+// @Param group query []string false "Filter by group, may be repeated to match any of groups" collectionFormat(multi)
+// @Param song query string false "Filter by song title"
+// @Param match query string false "Case-insensitive match mode of group and song filters" Enums(exact, prefix, contains) default(exact)
+// @Param release_date query string false "Filter by release date" Format("02.01.2006")
+// @Param release_from query string false "Filter by release date from (inclusive)" Format("02.01.2006")
+// @Param release_to query string false "Filter by release date to (inclusive)" Format("02.01.2006")
+// @Param created_after query string false "Filter songs created after timestamp" Format(date-time)
+// @Param updated_after query string false "Filter songs updated after timestamp" Format(date-time)
+// @Param as_of query string false "Export songs as they were at given moment" Format(date-time)
+// @Param sort query string false "Comma separated sort columns, prefixed with - for descending order" default(-release_date)
+// @Param collation query string false "Collation of text columns in sort" Enums(ru, en)
+// @Success 200 {file} file "Exported songs"
+// @Header 200 {string} Content-Disposition "Name of export file"
+// @Failure 400 {string} string "Неправильный фильтр или Неправильный параметр"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/export [get]
+func (h *Handler) ExportSongsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter, err := parseSongFilters(query)
+	if writeFilterError(w, err) {
+		return
+	}
+	sort, err := parseSongSort(query)
+	if writeFilterError(w, err) {
+		return
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = models.FormatCSV
+	}
+	var contentType string
+	switch format {
+	case models.FormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case models.FormatNDJSON:
+		contentType = "application/x-ndjson"
+	case models.FormatJSON:
+		contentType = "application/json"
+	default:
+		http.Error(w, "Параметр format должен быть одним из: csv, ndjson, json", http.StatusBadRequest)
+		return
+	}
+
+	columns, err := parseExportColumns(query.Get("columns"))
+	if writeFilterError(w, err) {
+		return
+	}
+
+	// Response is streamed for as long as export takes, so server write timeout is replaced with bulk one
+	h.extendDeadlines(w, false)
+
+	// Response is started with the first song, so errors found before it are still reported with status
+	buffered := bufio.NewWriter(w)
+	var writer songWriter
+	var exported int
+	start := func() {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition",
+			`attachment; filename="songs-`+time.Now().Format("2006-01-02")+`.`+format+`"`)
+		writer = newSongWriter(format, buffered, columns)
+	}
+
+	err = h.service.ExportSongs(r.Context(), filter, sort, columns, func(song models.Song) error {
+		if writer == nil {
+			start()
+		}
+		if err := writer.write(song); err != nil {
+			return err
+		}
+
+		exported++
+		if exported%exportFlushEvery == 0 {
+			if err := buffered.Flush(); err != nil {
+				return err
+			}
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if writer == nil {
+			if writeFilterError(w, err) {
+				return
+			}
+			http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+			return
+		}
+
+		// Part of export is already sent, so connection is aborted to let client know export is incomplete
+		h.logger.Errorf("ExportSongsHandler[handler]: Выгрузка прервана после %d песен: %v", exported, err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if writer == nil {
+		start()
+	}
+	if err = writer.close(); err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		h.logger.Errorf("ExportSongsHandler[handler]: Ошибка записи выгрузки: %v", err)
+	}
+}
+
+// parseExportColumns parses comma separated list of exported columns, all columns are exported by default
+func parseExportColumns(value string) ([]string, error) {
+	if value == "" {
+		return exportColumns, nil
+	}
+
+	var columns []string
+	seen := make(map[string]bool)
+	for _, column := range strings.Split(value, ",") {
+		column = strings.TrimSpace(column)
+		known := false
+		for _, name := range exportColumns {
+			known = known || name == column
+		}
+		if !known {
+			return nil, &paramError{message: "Неизвестная колонка выгрузки: " + column}
+		}
+		if seen[column] {
+			return nil, &paramError{message: "Колонка выгрузки указана несколько раз: " + column}
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// newSongWriter creates writer of songs in given format
+func newSongWriter(format string, w *bufio.Writer, columns []string) songWriter {
+	switch format {
+	case models.FormatNDJSON:
+		return &jsonSongWriter{w: w, columns: columns, lines: true}
+	case models.FormatJSON:
+		return &jsonSongWriter{w: w, columns: columns}
+	default:
+		return &csvSongWriter{w: csv.NewWriter(w), columns: columns}
+	}
+}
+
+// csvSongWriter writes songs as CSV with header row
+type csvSongWriter struct {
+	w       *csv.Writer
+	columns []string
+	started bool
+}
+
+func (c *csvSongWriter) write(song models.Song) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		switch value := exportValue(song, column).(type) {
+		case string:
+			record[i] = value
+		case int:
+			record[i] = strconv.Itoa(value)
+		case time.Time:
+			record[i] = value.Format(time.RFC3339)
+		}
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvSongWriter) close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// writeHeader writes header row once, so export without songs still has it
+func (c *csvSongWriter) writeHeader() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.w.Write(c.columns)
+}
+
+// jsonSongWriter writes songs as JSON objects with selected columns in given order,
+// either one per line (NDJSON) or as elements of JSON array
+type jsonSongWriter struct {
+	w       *bufio.Writer
+	columns []string
+	lines   bool
+	count   int
+}
+
+func (j *jsonSongWriter) write(song models.Song) error {
+	if !j.lines {
+		separator := ","
+		if j.count == 0 {
+			separator = "["
+		}
+		if _, err := j.w.WriteString(separator); err != nil {
+			return err
+		}
+	}
+	j.count++
+
+	j.w.WriteByte('{')
+	for i, column := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		value, err := json.Marshal(exportValue(song, column))
+		if err != nil {
+			return err
+		}
+		j.w.Write(key)
+		j.w.WriteByte(':')
+		j.w.Write(value)
+	}
+	j.w.WriteByte('}')
+
+	if j.lines {
+		j.w.WriteByte('\n')
+	}
+	return nil
+}
+
+func (j *jsonSongWriter) close() error {
+	if j.lines {
+		return nil
+	}
+	if j.count == 0 {
+		_, err := j.w.WriteString("[]")
+		return err
+	}
+	_, err := j.w.WriteString("]")
+	return err
+}
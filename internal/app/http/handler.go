@@ -4,117 +4,261 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/health"
 	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/musicinfo"
 	"rest-songs/internal/app/repository/postgresql"
 )
 
-// Handler struct wraps service interface, which interacts with business logic
+// Handler struct wraps service interface, which interacts with business logic,
+// music info provider used to fetch song details and checker of service health
 type Handler struct {
-	service     api.Service
-	externalAPI string
+	service   api.Service
+	musicInfo musicinfo.MusicInfoProvider
+	health    *health.Checker
+	// bulkTimeout replaces server read and write timeouts for import and export, which take longer
+	bulkTimeout time.Duration
 	logger      *logrus.Logger
 }
 
-// New creates new Handler instance and takes api.Service and logger as parameters
-func New(service api.Service, externalAPI string, logger *logrus.Logger) *Handler {
+// New creates new Handler instance and takes api.Service, musicinfo.MusicInfoProvider, health.Checker,
+// timeout of bulk requests and logger as parameters
+func New(service api.Service, musicInfo musicinfo.MusicInfoProvider, health *health.Checker, bulkTimeout time.Duration,
+	logger *logrus.Logger) *Handler {
 	return &Handler{
 		service:     service,
-		externalAPI: externalAPI,
+		musicInfo:   musicInfo,
+		health:      health,
+		bulkTimeout: bulkTimeout,
 		logger:      logger,
 	}
 }
 
+// extendDeadlines replaces server timeouts of bulk request with bulkTimeout, counted from now
+// Read deadline is extended only for requests which stream body, write deadline for every bulk request
+func (h *Handler) extendDeadlines(w http.ResponseWriter, read bool) {
+	if h.bulkTimeout <= 0 {
+		return
+	}
+	controller := http.NewResponseController(w)
+	deadline := time.Now().Add(h.bulkTimeout)
+	if read {
+		if err := controller.SetReadDeadline(deadline); err != nil {
+			h.logger.Warnf("extendDeadlines[handler]: Не удалось продлить таймаут чтения: %v", err)
+		}
+	}
+	if err := controller.SetWriteDeadline(deadline); err != nil {
+		h.logger.Warnf("extendDeadlines[handler]: Не удалось продлить таймаут записи: %v", err)
+	}
+}
+
 // GetSongsHandler handles GET request for filtering and retrieving songs
 // @Summary Get songs
-// @Description Get songs with optional filters and pagination
+// @Description Get songs with optional filters and pagination.
+// @Description Offset pagination is used by default. With pagination=cursor or cursor parameter keyset pagination is used:
+// @Description next_cursor and prev_cursor of response are passed back as cursor to get adjacent pages.
+// @Description Links to adjacent pages are also returned in Link header (RFC 8288)
 // @Tags Songs
 // @Accept json
 // @Produce json
-// @Param group query string false "Filter by group"
+// @Param group query []string false "Filter by group, may be repeated to match any of groups" collectionFormat(multi)
 // @Param song query string false "Filter by song title"
+// @Param match query string false "Case-insensitive match mode of group and song filters" Enums(exact, prefix, contains) default(exact)
 // @Param release_date query string false "Filter by release date" Format("02.01.2006")
+// @Param release_from query string false "Filter by release date from (inclusive)" Format("02.01.2006")
+// @Param release_to query string false "Filter by release date to (inclusive)" Format("02.01.2006")
+// @Param created_after query string false "Filter songs created after timestamp" Format(date-time)
+// @Param updated_after query string false "Filter songs updated after timestamp" Format(date-time)
+// @Param as_of query string false "List songs as they were at given moment" Format(date-time)
+// @Param sort query string false "Comma separated sort columns, prefixed with - for descending order: id, group, song, release_date, created_at, updated_at. id is always added as the final tiebreaker" default(-release_date)
+// @Param collation query string false "Collation of text columns in sort" Enums(ru, en)
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Number of items per page" default(10)
-// @Success 200 {array} models.Song
-// @Failure 400 {string} string "Неправильный формат данных"
+// @Param pagination query string false "Pagination mode" Enums(offset, cursor) default(offset)
+// @Param cursor query string false "Cursor of page (next_cursor or prev_cursor of previous response)"
+// @Param count query string false "Count mode of total number of songs (none by default in cursor mode)" Enums(exact, estimated, none) default(exact)
+// @Success 200 {object} models.Page[models.Song]
+// @Header 200 {string} Link "Links to first, prev, next and last pages"
+// @Failure 400 {string} string "Неправильный формат параметра или Неправильный фильтр"
 // @Failure 500 {string} string "Проблема на сервере"
 // @Router /songs [get]
 func (h *Handler) GetSongsHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
 	// Parse filter parameters
-	group := query.Get("group")
-	title := query.Get("song")
-	releaseDateStr := query.Get("release_date")
-
-	var releaseDate time.Time
-	var err error
-	if releaseDateStr != "" {
-		// Parse release date from string to time.Time format
-		releaseDate, err = time.Parse("02.01.2006", releaseDateStr)
-		if err != nil {
-			http.Error(w, "Неправильный формат даты", http.StatusBadRequest)
+	filter, err := parseSongFilters(query)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	// Parse sort parameters
+	sort, err := parseSongSort(query)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	// Use keyset pagination if cursor is given or requested explicitly
+	// Songs are not counted by default in this mode, since it is meant for deep pages of large lists
+	cursorMode := query.Has("cursor") || query.Get("pagination") == "cursor"
+	defaultCount := models.CountExact
+	if cursorMode {
+		defaultCount = models.CountNone
+	}
+
+	// Parse count mode of total number of songs
+	count, err := parseCountMode(query, defaultCount)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	if cursorMode {
+		h.getSongsByCursor(w, r, filter, sort, count)
+		return
+	}
+
+	// Parse pagination parameters
+	page, pageSize := parsePagination(query)
+
+	// Call service to get songs with filter
+	songs, err := h.service.GetSongsWithFilter(r.Context(), filter, sort, page, pageSize, count)
+	if err != nil {
+		// Return 400 error if filter is inconsistent
+		if writeFilterError(w, err) {
 			return
 		}
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
 	}
 
-	filter := models.SongFilters{
-		Group:       group,
-		Title:       title,
-		ReleaseDate: releaseDate,
+	// Respond with page of songs
+	setPageLinks(w, r, songs)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(songs)
+}
+
+// getSongsByCursor responds with page of songs selected by cursor along with cursors of adjacent pages
+func (h *Handler) getSongsByCursor(w http.ResponseWriter, r *http.Request, filter models.SongFilters, sort models.SongSort, count string) {
+	query := r.URL.Query()
+
+	var cursor *models.Cursor
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor = &models.Cursor{}
+		if err := cursor.UnmarshalText([]byte(cursorStr)); err != nil {
+			http.Error(w, "Неправильный курсор", http.StatusBadRequest)
+			return
+		}
 	}
 
-	// Parse pagination parameters
-	pageStr := query.Get("page")
-	pageSizeStr := query.Get("page_size")
+	// Page size is used as limit of page
+	_, limit := parsePagination(query)
 
-	// Convert page string to integer
-	page, err := strconv.Atoi(pageStr)
+	// Call service to get page of songs
+	page, err := h.service.GetSongsWithCursor(r.Context(), filter, sort, cursor, limit, count)
 	if err != nil {
-		page = 1 // default to page 1
+		// Return 400 error if filter is inconsistent or cursor doesn't fit order
+		if writeFilterError(w, err) {
+			return
+		}
+		if errors.Is(err, models.ErrInvalidCursor) {
+			http.Error(w, "Неправильный курсор", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
 	}
 
-	// Convert page size string to integer
-	pageSize, err := strconv.Atoi(pageSizeStr)
+	// Respond with page of songs and cursors
+	setPageLinks(w, r, page)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetSongByIdHandler handles GET requests to retrieve a song by its ID
+// @Summary Get song by ID
+// @Description Get song by its ID. Response carries ETag derived from song version and Last-Modified,
+// @Description which may be sent back in If-None-Match or If-Modified-Since to get 304 when song is unchanged
+// @Tags Songs
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-None-Match header string false "ETag of cached song"
+// @Param If-Modified-Since header string false "Last-Modified of cached song"
+// @Success 200 {object} models.Song "Song object"
+// @Header 200 {string} ETag "Version of song"
+// @Header 200 {string} Last-Modified "Time of last update of song"
+// @Success 304 "Song not modified"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id} [get]
+func (h *Handler) GetSongByIdHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	// Convert ID string to integer
+	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pageSize = 10 // default to 10 items per page
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
 	}
 
-	// Call service to get songs with filter
-	songs, err := h.service.GetSongsWithFilter(filter, page, pageSize)
+	// Call service to get song by ID
+	song, err := h.service.GetSongById(r.Context(), id)
 	if err != nil {
+		// Return 404 error if song not found
+		if errors.Is(err, postgresql.ErrSongNotFound) {
+			http.Error(w, "Песня не найдена", http.StatusNotFound)
+			return
+		}
+		// Return 500 error for other issue
 		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
 		return
 	}
 
-	// Respond with list of songs
-	w.Header().Set("Content-Type", "application/json")
-	if len(songs) == 0 {
-		json.NewEncoder(w).Encode("Песня не найдена/Список песен пуст")
+	// Respond with 304 if client has actual version of song
+	if notModified(r, song.Version, song.UpdatedAt) {
+		writeNotModified(w, song.Version, song.UpdatedAt)
 		return
 	}
-	json.NewEncoder(w).Encode(songs)
+
+	writeSong(w, http.StatusOK, song)
 }
 
 // GetSongTextHandler handles GET requests to retrieve paginated song text by song ID
 // @Summary Get paginated song text
-// @Description Get the verses of a song by its ID with optional pagination parameters
+// @Description Get the text of a song by its ID split into verses or lines, with optional pagination parameters.
+// @Description Verses are separated by one or more blank lines, line endings and trailing spaces are normalized.
+// @Description Lines are numbered through whole text, every verse and line carries index of its verse.
+// @Description Markers like [Chorus] or [Verse 2] start labelled sections, unmarked stanzas repeated in text are
+// @Description detected as chorus. Repeated verse refers to its first occurrence in repeat_of, and with collapse_repeats=true
+// @Description its lines are omitted.
+// @Description Conditional requests with If-None-Match or If-Modified-Since are answered with 304 when song is unchanged
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param unit query string false "Unit of pagination" Enums(verse, line) default(verse)
 // @Param page query int false "Page number" default(1)
-// @Param page_size query int false "Number of verses per page" default(10)
-// @Success 200 {array} string "Array of verses"
-// @Failure 400 {string} string "Неправильный формат ID или Страница выходит за пределы доступного диапазона"
+// @Param page_size query int false "Number of verses or lines per page" default(10)
+// @Param as_of query string false "Get text as it was at given moment" Format(date-time)
+// @Param collapse_repeats query bool false "Omit lines of repeated verses"
+// @Param If-None-Match header string false "ETag of cached text"
+// @Param If-Modified-Since header string false "Last-Modified of cached text"
+// @Success 200 {object} models.Page[models.Verse] "Page of verses (unit=verse) or models.Page[models.LyricsLine] (unit=line)"
+// @Header 200 {string} Link "Links to first, prev, next and last pages"
+// @Header 200 {string} ETag "Version of song"
+// @Success 304 "Song not modified"
+// @Failure 400 {string} string "Неправильный формат ID, Неправильный параметр unit или Страница выходит за пределы доступного диапазона"
 // @Failure 404 {string} string "Песня не найдена"
 // @Failure 500 {string} string "Проблема на сервере"
 // @Router /songs/text/{id} [get]
@@ -130,23 +274,30 @@ func (h *Handler) GetSongTextHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse pagination parameters from query
-	pageStr := r.URL.Query().Get("page")
-	pageSizeStr := r.URL.Query().Get("page_size")
+	query := r.URL.Query()
+	page, pageSize := parsePagination(query)
 
-	// Convert page string to integer
-	page, err := strconv.Atoi(pageStr)
-	if err != nil {
-		page = 1 // default to page 1
+	options := models.TextOptions{CollapseRepeats: query.Get("collapse_repeats") == "true"}
+	options.AsOf, err = parseTimeParam(query, "as_of")
+	if writeFilterError(w, err) {
+		return
 	}
 
-	// Convert page size string to integer
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil {
-		pageSize = 10 // default to 10 items per page
+	// Call service to get paginated song text in requested units
+	switch query.Get("unit") {
+	case "", models.TextUnitVerse:
+		verses, err := h.service.GetSongText(r.Context(), id, options, page, pageSize)
+		writeSongText(w, r, verses, err)
+	case models.TextUnitLine:
+		lines, err := h.service.GetSongLines(r.Context(), id, options, page, pageSize)
+		writeSongText(w, r, lines, err)
+	default:
+		http.Error(w, "Параметр unit должен быть одним из: verse, line", http.StatusBadRequest)
 	}
+}
 
-	// Call service to get paginated song text
-	verses, err := h.service.GetSongText(id, page, pageSize)
+// writeSongText responds with page of song text, or with 304 if client has actual version of song
+func writeSongText[T any](w http.ResponseWriter, r *http.Request, text models.SongText[T], err error) {
 	if err != nil {
 		// Return 404 error if song not found
 		if errors.Is(err, postgresql.ErrSongNotFound) {
@@ -165,9 +316,17 @@ func (h *Handler) GetSongTextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Respond with paginated verses
+	// Respond with 304 if client has actual version of song
+	if notModified(r, text.Version, text.UpdatedAt) {
+		writeNotModified(w, text.Version, text.UpdatedAt)
+		return
+	}
+
+	// Respond with paginated text
+	setPageLinks(w, r, text.Page)
+	setSongValidators(w, text.Version, text.UpdatedAt)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(verses)
+	json.NewEncoder(w).Encode(text)
 }
 
 // UpdateSongByIdHandler handles PUT requests to update a song by its ID
@@ -177,10 +336,13 @@ func (h *Handler) GetSongTextHandler(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of song, update is rejected with 412 if song was changed"
 // @Param song body object true "Song data to update"
 // @Success 200 {object} models.Song "Updated song object"
+// @Header 200 {string} ETag "New version of song"
 // @Failure 400 {string} string "Неправильный формат ID, Неправильный формат данных, or Неправильный формат даты"
 // @Failure 404 {string} string "Песня не найдена"
+// @Failure 412 {string} string "Версия песни не совпадает с If-Match"
 // @Failure 500 {string} string "Проблема на сервере"
 // @Router /songs/{id} [put]
 func (h *Handler) UpdateSongByIdHandler(w http.ResponseWriter, r *http.Request) {
@@ -203,7 +365,7 @@ func (h *Handler) UpdateSongByIdHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Decode request body into input struct
-	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil || strings.TrimSpace(input.Group) == "" {
 		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
 		return
 	}
@@ -219,14 +381,23 @@ func (h *Handler) UpdateSongByIdHandler(w http.ResponseWriter, r *http.Request)
 	song := models.Song{
 		Group:       input.Group,
 		Title:       input.Title,
-		ReleaseDate: releaseDate,
+		ReleaseDate: &releaseDate,
 		Text:        input.Text,
 		Link:        input.Link,
 	}
 
-	// Call service to update song by ID
-	updatedSong, err := h.service.UpdateSongById(id, song)
+	// Get version of song required by If-Match header
+	version, err := h.expectedVersion(r, id)
+	if err == nil {
+		// Call service to update song by ID
+		song, err = h.service.UpdateSongById(r.Context(), id, song, version)
+	}
 	if err != nil {
+		// Return 412 error if song was changed
+		if writePreconditionError(w, err) {
+			return
+		}
+
 		// Return 404 error if song not found
 		if errors.Is(err, postgresql.ErrSongNotFound) {
 			http.Error(w, "Песня не найдена", http.StatusNotFound)
@@ -239,18 +410,20 @@ func (h *Handler) UpdateSongByIdHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Respond with updated song
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedSong)
+	writeSong(w, http.StatusOK, song)
 }
 
 // DeleteSongByIdHandler handles DELETE requests to remove a song by its ID
 // @Summary Delete song by ID
-// @Description Delete an existing song by its ID from the database
+// @Description Move an existing song to trash by its ID. Song can be restored by POST /songs/{id}/restore
+// @Description until it is purged after retention period
 // @Tags Songs
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of song, deletion is rejected with 412 if song was changed"
 // @Success 204 "No Content - Successfully deleted"
 // @Failure 400 {string} string "Неправильный формат ID"
 // @Failure 404 {string} string "Песня не найдена"
+// @Failure 412 {string} string "Версия песни не совпадает с If-Match"
 // @Failure 500 {string} string "Проблема на сервере"
 // @Router /songs/{id} [delete]
 func (h *Handler) DeleteSongByIdHandler(w http.ResponseWriter, r *http.Request) {
@@ -264,9 +437,18 @@ func (h *Handler) DeleteSongByIdHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Call service to delete song by ID
-	err = h.service.DeleteSongById(id)
+	// Get version of song required by If-Match header
+	version, err := h.expectedVersion(r, id)
+	if err == nil {
+		// Call service to delete song by ID
+		err = h.service.DeleteSongById(r.Context(), id, version)
+	}
 	if err != nil {
+		// Return 412 error if song was changed
+		if writePreconditionError(w, err) {
+			return
+		}
+
 		// Return 404 error if song not found
 		if errors.Is(err, postgresql.ErrSongNotFound) {
 			http.Error(w, "Песня не найдена", http.StatusNotFound)
@@ -283,72 +465,131 @@ func (h *Handler) DeleteSongByIdHandler(w http.ResponseWriter, r *http.Request)
 
 // AddSongHandler handles POST requests to add a new song
 // @Summary Add a new song
-// @Description Create a new song by providing the group and song title
+// @Description Create a new song by providing the group and song title.
+// @Description Song is stored at once with pending enrichment status and its details are fetched in background.
+// @Description With sync=true details are fetched within request and song is created only on success
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param song body models.AddSongRequest true "Song details"
-// @Success 201 {object} models.Song "Created song"
+// @Param sync query bool false "Fetch song details synchronously"
+// @Success 201 {object} models.Song "Created song (sync=true)"
+// @Success 202 {object} models.Song "Song queued for enrichment"
 // @Failure 400 {string} string "Неправильный формат данных"
+// @Failure 404 {string} string "Песня не найдена во внешнем API"
 // @Failure 500 {string} string "Проблема на сервере"
+// @Failure 502 {string} string "Внешний API недоступен или Внешний API отклонил запрос"
+// @Failure 504 {string} string "Внешний API не ответил вовремя"
 // @Router /songs [post]
 func (h *Handler) AddSongHandler(w http.ResponseWriter, r *http.Request) {
 	var input models.AddSongRequest
 
 	// Decode request body into input struct
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil ||
+		strings.TrimSpace(input.Group) == "" || input.Song == "" {
 		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
 		return
 	}
 
-	h.logger.Infof("AddSongHandler[handler]: Получение деталей песни через API для группы: %s, песни: %s",
-		input.Group, input.Song)
+	if r.URL.Query().Get("sync") == "true" {
+		h.addSongSync(w, r, input)
+		return
+	}
 
-	// Encode group and song parameters for URL
-	group := url.QueryEscape(input.Group)
-	song := url.QueryEscape(input.Song)
+	// Call service to store song and queue it for enrichment
+	createdSong, err := h.service.EnqueueSong(r.Context(), input.Group, input.Song)
+	if err != nil {
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
+	}
 
-	mockserverURL := h.externalAPI + "/info?group=" + group + "&song=" + song
+	// Respond with accepted song and location of its enrichment status
+	w.Header().Set("Location", "/songs/"+strconv.Itoa(createdSong.ID)+"/enrichment")
+	writeSong(w, http.StatusAccepted, createdSong)
+}
 
-	h.logger.Infof("AddSongHandler[handler]: mockserverURL: %s",
-		mockserverURL)
+// addSongSync fetches song details from music info service and creates song within request
+// Request to music info service is cancelled when client disconnects
+func (h *Handler) addSongSync(w http.ResponseWriter, r *http.Request, input models.AddSongRequest) {
+	h.logger.Infof("AddSongHandler[handler]: Получение деталей песни через API для группы: %s, песни: %s",
+		input.Group, input.Song)
 
-	// get song details from mockserver
-	resp, err := http.Get(mockserverURL)
+	// Get song details from music info service
+	songDetails, err := h.musicInfo.GetSongDetail(r.Context(), input.Group, input.Song)
 	if err != nil {
-		h.logger.Errorf("AddSongHandler[handler]: Ошибка отправки запроса к API: %v", err)
-		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		h.logger.Errorf("AddSongHandler[handler]: Ошибка получения деталей песни: %v", err)
+		writeMusicInfoError(w, err)
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		h.logger.Errorf("AddSongHandler[handler]: API вернул ошибку: %s", resp.Status)
+	h.logger.Infof("AddSongHandler[handler]: Успешно получены детали песни через API")
+
+	// Call service to create song
+	createdSong, err := h.service.CreateSong(r.Context(), input.Group, input.Song, songDetails)
+	if err != nil {
 		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
 		return
 	}
 
-	// Parse API response
-	var songDetails models.SongDetail
-	if err = json.NewDecoder(resp.Body).Decode(&songDetails); err != nil {
-		h.logger.Errorf("AddSongHandler[handler]: Ошибка парсинга ответа от API: %v", err)
-		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	// Respond with created song
+	w.Header().Set("Location", "/songs/"+strconv.Itoa(createdSong.ID))
+	writeSong(w, http.StatusCreated, createdSong)
+}
+
+// GetSongEnrichmentHandler handles GET requests to retrieve enrichment status of song by its ID
+// @Summary Get song enrichment status
+// @Description Get status of background fetching of song details, number of attempts and last error
+// @Tags Songs
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} models.SongEnrichment
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/enrichment [get]
+func (h *Handler) GetSongEnrichmentHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	// Convert ID string to integer
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
 		return
 	}
 
-	h.logger.Infof("AddSongHandler[handler]: Успешно получены детали песни через API")
-
-	// Call service to create song
-	createdSong, err := h.service.CreateSong(input.Group, input.Song, songDetails)
+	// Call service to get enrichment status
+	enrichment, err := h.service.GetSongEnrichment(r.Context(), id)
 	if err != nil {
+		// Return 404 error if song not found
+		if errors.Is(err, postgresql.ErrSongNotFound) {
+			http.Error(w, "Песня не найдена", http.StatusNotFound)
+			return
+		}
+		// Return 500 error for other issue
 		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
 		return
 	}
 
-	// Respond with created song
+	// Respond with enrichment status
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdSong)
+	json.NewEncoder(w).Encode(enrichment)
+}
+
+// writeMusicInfoError maps music info provider error to HTTP response
+func writeMusicInfoError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, musicinfo.ErrNotFound):
+		http.Error(w, "Песня не найдена во внешнем API", http.StatusNotFound)
+	case errors.Is(err, musicinfo.ErrRejected):
+		http.Error(w, "Внешний API отклонил запрос", http.StatusBadGateway)
+	case errors.Is(err, musicinfo.ErrTimeout):
+		http.Error(w, "Внешний API не ответил вовремя", http.StatusGatewayTimeout)
+	case errors.Is(err, musicinfo.ErrUnavailable), errors.Is(err, musicinfo.ErrCircuitOpen):
+		http.Error(w, "Внешний API недоступен", http.StatusBadGateway)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
 }
 
 // RegisterRoutes registers HTTP routes for song operations
@@ -357,18 +598,132 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	// @Router /songs [get]
 	r.HandleFunc("/songs", h.GetSongsHandler).Methods("GET")
 
+	// @Router /songs/import [post]
+	r.HandleFunc("/songs/import", h.ImportSongsHandler).Methods("POST")
+
+	// @Router /songs/export [get]
+	r.HandleFunc("/songs/export", h.ExportSongsHandler).Methods("GET")
+
+	// @Router /songs/search [get]
+	r.HandleFunc("/songs/search", h.SearchSongsHandler).Methods("GET")
+
+	// @Router /songs/trash [get]
+	r.HandleFunc("/songs/trash", h.GetDeletedSongsHandler).Methods("GET")
+
 	// @Router /songs/text/{id} [get]
 	r.HandleFunc("/songs/text/{id}", h.GetSongTextHandler).Methods("GET")
 
+	// @Router /songs/{id} [get]
+	r.HandleFunc("/songs/{id:[0-9]+}", h.GetSongByIdHandler).Methods("GET")
+
 	// @Router /songs/{id} [put]
 	r.HandleFunc("/songs/{id}", h.UpdateSongByIdHandler).Methods("PUT")
 
+	// @Router /songs/{id} [patch]
+	r.HandleFunc("/songs/{id}", h.PatchSongByIdHandler).Methods("PATCH")
+
 	// @Router /songs/{id} [delete]
 	r.HandleFunc("/songs/{id}", h.DeleteSongByIdHandler).Methods("DELETE")
 
 	// @Router /songs [post]
 	r.HandleFunc("/songs", h.AddSongHandler).Methods("POST")
 
+	// @Router /songs/{id}/restore [post]
+	r.HandleFunc("/songs/{id}/restore", h.RestoreSongByIdHandler).Methods("POST")
+
+	// @Router /songs/{id}/revisions [get]
+	r.HandleFunc("/songs/{id}/revisions", h.GetSongRevisionsHandler).Methods("GET")
+
+	// @Router /songs/{id}/revisions/diff [get]
+	r.HandleFunc("/songs/{id}/revisions/diff", h.DiffSongRevisionsHandler).Methods("GET")
+
+	// @Router /songs/{id}/revisions/{rev} [get]
+	r.HandleFunc("/songs/{id}/revisions/{rev:[0-9]+}", h.GetSongRevisionHandler).Methods("GET")
+
+	// @Router /songs/{id}/revisions/{rev}/revert [post]
+	r.HandleFunc("/songs/{id}/revisions/{rev:[0-9]+}/revert", h.RevertSongRevisionHandler).Methods("POST")
+
+	// @Router /songs/{id}/lyrics.json [get]
+	r.HandleFunc("/songs/{id}/lyrics.json", h.GetSyncedLyricsHandler).Methods("GET")
+
+	// @Router /songs/{id}/lyrics.json [put]
+	r.HandleFunc("/songs/{id}/lyrics.json", h.SetSyncedLyricsHandler).Methods("PUT")
+
+	// @Router /songs/{id}/lyrics.lrc [get]
+	r.HandleFunc("/songs/{id}/lyrics.lrc", h.ExportLRCHandler).Methods("GET")
+
+	// @Router /songs/{id}/lyrics.lrc [put]
+	r.HandleFunc("/songs/{id}/lyrics.lrc", h.ImportLRCHandler).Methods("PUT")
+
+	// @Router /songs/{id}/chords [get]
+	r.HandleFunc("/songs/{id}/chords", h.GetSongChordsHandler).Methods("GET")
+
+	// @Router /songs/{id}/chords [put]
+	r.HandleFunc("/songs/{id}/chords", h.SetSongChordsHandler).Methods("PUT")
+
+	// @Router /songs/{id}/chords [delete]
+	r.HandleFunc("/songs/{id}/chords", h.DeleteSongChordsHandler).Methods("DELETE")
+
+	// @Router /songs/{id}/enrichment [get]
+	r.HandleFunc("/songs/{id}/enrichment", h.GetSongEnrichmentHandler).Methods("GET")
+
+	// @Router /groups [get]
+	r.HandleFunc("/groups", h.GetArtistsHandler).Methods("GET")
+
+	// @Router /groups [post]
+	r.HandleFunc("/groups", h.AddArtistHandler).Methods("POST")
+
+	// @Router /groups/{id} [get]
+	r.HandleFunc("/groups/{id}", h.GetArtistByIdHandler).Methods("GET")
+
+	// @Router /groups/{id} [put]
+	r.HandleFunc("/groups/{id}", h.UpdateArtistByIdHandler).Methods("PUT")
+
+	// @Router /groups/{id} [delete]
+	r.HandleFunc("/groups/{id}", h.DeleteArtistByIdHandler).Methods("DELETE")
+
+	// @Router /groups/{id}/songs [get]
+	r.HandleFunc("/groups/{id}/songs", h.GetArtistSongsHandler).Methods("GET")
+
+	// @Router /playlists [get]
+	r.HandleFunc("/playlists", h.GetPlaylistsHandler).Methods("GET")
+
+	// @Router /playlists [post]
+	r.HandleFunc("/playlists", h.AddPlaylistHandler).Methods("POST")
+
+	// @Router /playlists/{id} [get]
+	r.HandleFunc("/playlists/{id}", h.GetPlaylistByIdHandler).Methods("GET")
+
+	// @Router /playlists/{id} [put]
+	r.HandleFunc("/playlists/{id}", h.UpdatePlaylistByIdHandler).Methods("PUT")
+
+	// @Router /playlists/{id} [delete]
+	r.HandleFunc("/playlists/{id}", h.DeletePlaylistByIdHandler).Methods("DELETE")
+
+	// @Router /playlists/{id}/items [post]
+	r.HandleFunc("/playlists/{id}/items", h.AddPlaylistItemHandler).Methods("POST")
+
+	// @Router /playlists/{id}/items/{item} [delete]
+	r.HandleFunc("/playlists/{id}/items/{item}", h.RemovePlaylistItemHandler).Methods("DELETE")
+
+	// @Router /playlists/{id}/items/{item}/move [post]
+	r.HandleFunc("/playlists/{id}/items/{item}/move", h.MovePlaylistItemHandler).Methods("POST")
+
+	// @Router /admin/keys [get]
+	r.HandleFunc("/admin/keys", h.GetAPIKeysHandler).Methods("GET")
+
+	// @Router /admin/keys [post]
+	r.HandleFunc("/admin/keys", h.IssueAPIKeyHandler).Methods("POST")
+
+	// @Router /admin/keys/{id} [delete]
+	r.HandleFunc("/admin/keys/{id}", h.RevokeAPIKeyHandler).Methods("DELETE")
+
+	// @Router /healthz [get]
+	r.HandleFunc("/healthz", h.LivenessHandler).Methods("GET")
+
+	// @Router /readyz [get]
+	r.HandleFunc("/readyz", h.ReadinessHandler).Methods("GET")
+
 	// Swagger documentation endpoint
 	r.PathPrefix("/docs/swagger/").Handler(httpSwagger.WrapHandler)
 	r.HandleFunc("/docs/swagger/index.html", httpSwagger.WrapHandler)
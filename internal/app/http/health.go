@@ -0,0 +1,46 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"rest-songs/internal/app/models"
+)
+
+// LivenessHandler handles GET requests to check that service is alive
+// @Summary Liveness probe
+// @Description Service is alive as long as it answers, dependencies are not checked
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthReport
+// @Router /healthz [get]
+func (h *Handler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, h.health.Live())
+}
+
+// ReadinessHandler handles GET requests to check that service is ready to serve requests
+// @Summary Readiness probe
+// @Description Check database connection and schema version, and optionally music info service.
+// @Description Failure of music info service makes service degraded, but still ready.
+// @Description Service is reported as not ready while it is shutting down
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthReport "Service is ready (status ok or degraded)"
+// @Failure 503 {object} models.HealthReport "Service is not ready"
+// @Router /readyz [get]
+func (h *Handler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, h.health.Ready(r.Context()))
+}
+
+// writeHealthReport responds with report, status code is 503 if service is not ready
+func writeHealthReport(w http.ResponseWriter, report models.HealthReport) {
+	status := http.StatusOK
+	if report.Status == models.HealthFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
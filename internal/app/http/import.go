@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+)
+
+// importContentTypes maps content types of import body to import formats
+var importContentTypes = map[string]string{
+	"text/csv":             models.FormatCSV,
+	"application/x-ndjson": models.FormatNDJSON,
+	"application/ndjson":   models.FormatNDJSON,
+}
+
+// ImportSongsHandler handles POST requests for bulk import of songs
+// @Summary Import songs
+// @Description Import full song records from CSV (header row with columns group, song, release_date, text, link)
+// @Description or NDJSON (one JSON object per line with the same fields). Body is read as a stream and valid rows
+// @Description are loaded in batches within single transaction, invalid rows are rejected with reason.
+// @Description With mode=upsert song with the same group and title is updated instead of adding new one.
+// @Description With dry_run=true nothing is changed, but report shows what would be done
+// @Tags Songs
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Format of body, taken from Content-Type by default" Enums(csv, ndjson)
+// @Param mode query string false "Import mode" Enums(insert, upsert) default(insert)
+// @Param dry_run query bool false "Validate and report without changing library"
+// @Param body body string true "CSV or NDJSON song records"
+// @Success 200 {object} models.ImportReport "Report of accepted and rejected rows"
+// @Failure 400 {string} string "Неправильный импорт"
+// @Failure 415 {string} string "Неподдерживаемый тип содержимого"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/import [post]
+func (h *Handler) ImportSongsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	options := models.ImportOptions{
+		Format: query.Get("format"),
+		Mode:   query.Get("mode"),
+		DryRun: query.Get("dry_run") == "true",
+	}
+	if options.Format == "" {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format, ok := importContentTypes[contentType]
+		if !ok {
+			http.Error(w, "Неподдерживаемый тип содержимого, ожидается text/csv или application/x-ndjson",
+				http.StatusUnsupportedMediaType)
+			return
+		}
+		options.Format = format
+	}
+
+	// Body is streamed for as long as import takes, so server timeouts are replaced with bulk one
+	h.extendDeadlines(w, true)
+
+	// Call service to import songs
+	report, err := h.service.ImportSongs(r.Context(), r.Body, options)
+	if err != nil {
+		var importErr *api.ImportError
+		if errors.As(err, &importErr) {
+			http.Error(w, "Неправильный импорт: "+importErr.Reason, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
+	}
+
+	// Respond with import report
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+// parseCountMode parses count parameter, returning defaultMode if it is not set
+func parseCountMode(query url.Values, defaultMode string) (string, error) {
+	switch count := query.Get("count"); count {
+	case "":
+		return defaultMode, nil
+	case models.CountExact, models.CountEstimated, models.CountNone:
+		return count, nil
+	default:
+		return "", &paramError{message: "Параметр count должен быть одним из: exact, estimated, none"}
+	}
+}
+
+// setPageLinks sets RFC 8288 Link header pointing to first, previous, next and last pages
+// Links of cursor pages carry cursors, links of offset pages carry page numbers
+func setPageLinks[T any](w http.ResponseWriter, r *http.Request, page models.Page[T]) {
+	var links []string
+	addLink := func(rel string, set func(url.Values)) {
+		query := r.URL.Query()
+		set(query)
+		links = append(links, `<`+r.URL.Path+`?`+query.Encode()+`>; rel="`+rel+`"`)
+	}
+	setPage := func(n int64) func(url.Values) {
+		return func(query url.Values) {
+			query.Set("page", strconv.FormatInt(n, 10))
+		}
+	}
+	setCursor := func(cursor *models.Cursor) func(url.Values) {
+		return func(query url.Values) {
+			text, _ := cursor.MarshalText()
+			query.Set("cursor", string(text))
+		}
+	}
+
+	if page.Page == 0 {
+		// Cursor mode
+		addLink("first", func(query url.Values) {
+			query.Del("cursor")
+			query.Set("pagination", "cursor")
+		})
+		if page.PrevCursor != nil {
+			addLink("prev", setCursor(page.PrevCursor))
+		}
+		if page.NextCursor != nil {
+			addLink("next", setCursor(page.NextCursor))
+		}
+	} else {
+		current := int64(page.Page)
+		addLink("first", setPage(1))
+		if current > 1 {
+			addLink("prev", setPage(current-1))
+		}
+		if page.TotalPages != nil {
+			if current < *page.TotalPages {
+				addLink("next", setPage(current+1))
+			}
+			last := *page.TotalPages
+			if last < 1 {
+				last = 1
+			}
+			addLink("last", setPage(last))
+		} else if len(page.Items) == page.PageSize {
+			// Without total, full page suggests there may be more items
+			addLink("next", setPage(current+1))
+		}
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+)
+
+// paramError describes malformed query parameter, its message is returned to client as is
+type paramError struct {
+	message string
+}
+
+func (e *paramError) Error() string {
+	return e.message
+}
+
+// parseSongFilters parses song filter parameters from query
+// group may be repeated to match any of several groups
+func parseSongFilters(query url.Values) (models.SongFilters, error) {
+	filter := models.SongFilters{
+		Title: query.Get("song"),
+		Match: query.Get("match"),
+	}
+
+	for _, group := range query["group"] {
+		if group = strings.TrimSpace(group); group != "" {
+			filter.Groups = append(filter.Groups, group)
+		}
+	}
+
+	var err error
+	if filter.ReleaseDate, err = parseDateParam(query, "release_date"); err != nil {
+		return models.SongFilters{}, err
+	}
+	if filter.ReleaseFrom, err = parseDateParam(query, "release_from"); err != nil {
+		return models.SongFilters{}, err
+	}
+	if filter.ReleaseTo, err = parseDateParam(query, "release_to"); err != nil {
+		return models.SongFilters{}, err
+	}
+	if filter.CreatedAfter, err = parseTimeParam(query, "created_after"); err != nil {
+		return models.SongFilters{}, err
+	}
+	if filter.UpdatedAfter, err = parseTimeParam(query, "updated_after"); err != nil {
+		return models.SongFilters{}, err
+	}
+	if filter.AsOf, err = parseTimeParam(query, "as_of"); err != nil {
+		return models.SongFilters{}, err
+	}
+
+	return filter, nil
+}
+
+// parseDateParam parses optional date parameter given in "02.01.2006" format
+func parseDateParam(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	// Parse date from string to time.Time format
+	date, err := time.Parse("02.01.2006", value)
+	if err != nil {
+		return time.Time{}, &paramError{message: "Неправильный формат даты в параметре " + name}
+	}
+	return date, nil
+}
+
+// parseTimeParam parses optional timestamp parameter given in RFC 3339 or "02.01.2006" format
+func parseTimeParam(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("02.01.2006", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, &paramError{message: "Неправильный формат времени в параметре " + name}
+}
+
+// parseSongSort parses sort parameter, a comma separated list of columns, each optionally prefixed with "-"
+// for descending order, e.g. "-release_date,group,song", and collation parameter
+func parseSongSort(query url.Values) (models.SongSort, error) {
+	sort := models.SongSort{Collation: query.Get("collation")}
+
+	sortStr := query.Get("sort")
+	if sortStr == "" {
+		return sort, nil
+	}
+
+	for _, part := range strings.Split(sortStr, ",") {
+		part = strings.TrimSpace(part)
+		field := models.SortField{Column: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if field.Column == "" {
+			return models.SongSort{}, &paramError{message: "Неправильный формат параметра sort"}
+		}
+		sort.Fields = append(sort.Fields, field)
+	}
+
+	return sort, nil
+}
+
+// parsePagination parses page and page_size parameters from query, defaulting to first page of 10 items
+func parsePagination(query url.Values) (int, int) {
+	// Convert page string to integer
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1 // default to page 1
+	}
+
+	// Convert page size string to integer
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default to 10 items per page
+	}
+
+	return page, pageSize
+}
+
+// writeFilterError responds with 400 if err is caused by malformed or inconsistent filter parameters
+// It returns false if err is of another kind and was not handled
+func writeFilterError(w http.ResponseWriter, err error) bool {
+	var paramErr *paramError
+	if errors.As(err, &paramErr) {
+		http.Error(w, paramErr.message, http.StatusBadRequest)
+		return true
+	}
+
+	var filterErr *api.FilterError
+	if errors.As(err, &filterErr) {
+		http.Error(w, "Неправильный фильтр: "+filterErr.Reason, http.StatusBadRequest)
+		return true
+	}
+
+	return false
+}
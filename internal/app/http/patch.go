@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// Content types of patch documents
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// PatchSongByIdHandler handles PATCH requests to partially update a song by its ID
+// @Summary Partially update song by ID
+// @Description Update only given fields of song. Body is either JSON Merge Patch (RFC 7396),
+// @Description e.g. {"link": "https://..."}, or JSON Patch (RFC 6902), e.g. [{"op": "replace", "path": "/link", "value": "https://..."}].
+// @Description Removing text or link leaves it empty, group, song and release_date can't be removed
+// @Tags Songs
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of song, patch is rejected with 412 if song was changed"
+// @Param patch body object true "Patch document"
+// @Success 200 {object} models.Song "Updated song object"
+// @Header 200 {string} ETag "New version of song"
+// @Failure 400 {string} string "Неправильный формат ID, Неправильный формат данных или Неправильный патч"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 409 {string} string "Проверка test не пройдена"
+// @Failure 412 {string} string "Версия песни не совпадает с If-Match"
+// @Failure 415 {string} string "Неподдерживаемый тип содержимого"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id} [patch]
+func (h *Handler) PatchSongByIdHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	// Convert ID string to integer
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Get version of song required by If-Match header
+	version, err := h.expectedVersion(r, id)
+	if err != nil {
+		writePatchError(w, err)
+		return
+	}
+
+	var patch models.SongPatch
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case mergePatchContentType:
+		var doc map[string]interface{}
+		if err = json.NewDecoder(r.Body).Decode(&doc); err != nil {
+			http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+			return
+		}
+		patch, err = api.MergePatch(doc)
+
+	case jsonPatchContentType:
+		var operations []models.PatchOperation
+		if err = json.NewDecoder(r.Body).Decode(&operations); err != nil {
+			http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+			return
+		}
+
+		// JSON Patch operations are applied to current state of song
+		song, getErr := h.service.GetSongById(r.Context(), id)
+		if getErr != nil {
+			writePatchError(w, getErr)
+			return
+		}
+		if version != 0 && version != song.Version {
+			writePatchError(w, postgresql.ErrVersionMismatch)
+			return
+		}
+		// Patch is computed from this state, so it is applied only if song is not changed meanwhile
+		version = song.Version
+		patch, err = api.JSONPatch(song, operations)
+
+	default:
+		http.Error(w, "Неподдерживаемый тип содержимого, ожидается "+mergePatchContentType+
+			" или "+jsonPatchContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		writePatchError(w, err)
+		return
+	}
+
+	// Call service to update given fields of song
+	updatedSong, err := h.service.PatchSongById(r.Context(), id, patch, version)
+	if err != nil {
+		writePatchError(w, err)
+		return
+	}
+
+	// Respond with updated song
+	writeSong(w, http.StatusOK, updatedSong)
+}
+
+// writePatchError maps error of song patching to HTTP response
+func writePatchError(w http.ResponseWriter, err error) {
+	if writePreconditionError(w, err) {
+		return
+	}
+
+	var patchErr *api.PatchError
+	switch {
+	case errors.As(err, &patchErr):
+		http.Error(w, "Неправильный патч: "+patchErr.Reason, http.StatusBadRequest)
+	case errors.Is(err, api.ErrPatchTestFailed):
+		http.Error(w, "Проверка test не пройдена", http.StatusConflict)
+	case errors.Is(err, postgresql.ErrSongNotFound):
+		http.Error(w, "Песня не найдена", http.StatusNotFound)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
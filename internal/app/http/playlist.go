@@ -0,0 +1,299 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// GetPlaylistsHandler handles GET request for retrieving list of playlists
+// @Summary Get playlists
+// @Description Get playlists ordered by name with pagination
+// @Tags Playlists
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(10)
+// @Success 200 {object} models.Page[models.Playlist] "Page of playlists"
+// @Header 200 {string} Link "Links to first, prev, next and last pages"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists [get]
+func (h *Handler) GetPlaylistsHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination parameters
+	page, pageSize := parsePagination(r.URL.Query())
+
+	// Call service to get playlists
+	playlists, err := h.service.GetPlaylists(r.Context(), page, pageSize)
+	if err != nil {
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
+	}
+
+	setPageLinks(w, r, playlists)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(playlists)
+}
+
+// GetPlaylistByIdHandler handles GET requests to retrieve playlist with its songs
+// @Summary Get playlist by ID
+// @Description Get playlist with its entries in order, each with summary of its song.
+// @Description Songs in trash are hidden from playlist and don't take positions
+// @Tags Playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} models.PlaylistDetail
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists/{id} [get]
+func (h *Handler) GetPlaylistByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	playlist, err := h.service.GetPlaylistById(r.Context(), id)
+	if err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(playlist)
+}
+
+// AddPlaylistHandler handles POST requests to add a new playlist
+// @Summary Add a new playlist
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param playlist body models.PlaylistRequest true "Playlist name and description"
+// @Success 201 {object} models.Playlist "Created playlist"
+// @Failure 400 {string} string "Неправильный формат данных"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists [post]
+func (h *Handler) AddPlaylistHandler(w http.ResponseWriter, r *http.Request) {
+	var input models.PlaylistRequest
+
+	// Decode request body into input struct
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	playlist, err := h.service.CreatePlaylist(r.Context(), input)
+	if err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(playlist)
+}
+
+// UpdatePlaylistByIdHandler handles PUT requests to update playlist by its ID
+// @Summary Update playlist by ID
+// @Description Set name and description of playlist, entries are not changed
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param playlist body models.PlaylistRequest true "Playlist name and description"
+// @Success 200 {object} models.Playlist "Updated playlist"
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный формат данных"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists/{id} [put]
+func (h *Handler) UpdatePlaylistByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.PlaylistRequest
+
+	// Decode request body into input struct
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	playlist, err := h.service.UpdatePlaylist(r.Context(), id, input)
+	if err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(playlist)
+}
+
+// DeletePlaylistByIdHandler handles DELETE requests to remove playlist by its ID
+// @Summary Delete playlist by ID
+// @Description Delete playlist with all its entries, songs are kept
+// @Tags Playlists
+// @Param id path int true "Playlist ID"
+// @Success 204 "No Content - Successfully deleted"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists/{id} [delete]
+func (h *Handler) DeletePlaylistByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.DeletePlaylist(r.Context(), id); err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddPlaylistItemHandler handles POST requests to add song to playlist
+// @Summary Add song to playlist
+// @Description Insert song at 1-based position, entries from that position shift down.
+// @Description Position 0 or past the end appends song. The same song may be added several times
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param item body models.PlaylistItemRequest true "Song and position"
+// @Success 201 {object} models.PlaylistItem "Added entry"
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный формат данных или Неправильная позиция"
+// @Failure 404 {string} string "Плейлист не найден или Песня не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists/{id}/items [post]
+func (h *Handler) AddPlaylistItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	var input models.PlaylistItemRequest
+
+	// Decode request body into input struct
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.AddPlaylistItem(r.Context(), id, input)
+	if err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// MovePlaylistItemHandler handles POST requests to move entry of playlist
+// @Summary Move playlist entry
+// @Description Move entry to 1-based position, entries between old and new positions shift by one.
+// @Description Position past the end moves entry to the end
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param item path int true "Entry ID"
+// @Param move body models.PlaylistMoveRequest true "New position"
+// @Success 200 {object} models.PlaylistItem "Moved entry"
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный формат данных или Неправильная позиция"
+// @Failure 404 {string} string "Плейлист не найден или Элемент плейлиста не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists/{id}/items/{item}/move [post]
+func (h *Handler) MovePlaylistItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := parsePlaylistItemPath(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.PlaylistMoveRequest
+
+	// Decode request body into input struct
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.MovePlaylistItem(r.Context(), id, itemID, input.Position)
+	if err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// RemovePlaylistItemHandler handles DELETE requests to remove entry from playlist
+// @Summary Remove playlist entry
+// @Tags Playlists
+// @Param id path int true "Playlist ID"
+// @Param item path int true "Entry ID"
+// @Success 204 "No Content - Successfully deleted"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Плейлист не найден или Элемент плейлиста не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /playlists/{id}/items/{item} [delete]
+func (h *Handler) RemovePlaylistItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := parsePlaylistItemPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemovePlaylistItem(r.Context(), id, itemID); err != nil {
+		writePlaylistError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parsePlaylistItemPath parses playlist ID and entry ID from path, responding with 400 if they are invalid
+func parsePlaylistItemPath(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	itemID, err := strconv.Atoi(vars["item"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID элемента", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, itemID, true
+}
+
+// writePlaylistError maps playlist service error to HTTP response
+func writePlaylistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, api.ErrEmptyPlaylistName):
+		http.Error(w, "Неправильный формат данных", http.StatusBadRequest)
+	case errors.Is(err, api.ErrInvalidPosition):
+		http.Error(w, "Неправильная позиция: ожидается номер начиная с 1", http.StatusBadRequest)
+	case errors.Is(err, postgresql.ErrPlaylistNotFound):
+		http.Error(w, "Плейлист не найден", http.StatusNotFound)
+	case errors.Is(err, postgresql.ErrPlaylistItemNotFound):
+		http.Error(w, "Элемент плейлиста не найден", http.StatusNotFound)
+	case errors.Is(err, postgresql.ErrSongNotFound):
+		http.Error(w, "Песня не найдена", http.StatusNotFound)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
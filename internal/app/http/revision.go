@@ -0,0 +1,193 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	// models is referenced only by swag annotations, which resolve types through imports of file
+	_ "rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// GetSongRevisionsHandler handles GET requests to retrieve history of song changes
+// @Summary Get song revisions
+// @Description Get snapshots of song taken on every create, update, delete and restore, newest first.
+// @Description Revision number equals version (ETag) of song after change. History of deleted songs is kept until purge
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(10)
+// @Success 200 {object} models.Page[models.SongRevision] "Page of revisions"
+// @Header 200 {string} Link "Links to first, prev, next and last pages"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/revisions [get]
+func (h *Handler) GetSongRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse pagination parameters
+	page, pageSize := parsePagination(r.URL.Query())
+
+	// Call service to get revisions
+	revisions, err := h.service.GetSongRevisions(r.Context(), id, page, pageSize)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	setPageLinks(w, r, revisions)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// GetSongRevisionHandler handles GET requests to retrieve single revision of song
+// @Summary Get song revision
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.SongRevision
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Ревизия не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/revisions/{rev} [get]
+func (h *Handler) GetSongRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id, revision, ok := parseRevisionPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Call service to get revision
+	result, err := h.service.GetSongRevision(r.Context(), id, revision)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// DiffSongRevisionsHandler handles GET requests to compare two revisions of song
+// @Summary Diff song revisions
+// @Description Get fields changed between revisions and line diff of text.
+// @Description Each line of diff is equal, insert or delete, with its line numbers in texts of revisions from and to.
+// @Description Revision from may be newer than to, then diff shows changes backwards
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param from query int true "Revision to compare from"
+// @Param to query int true "Revision to compare to"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный диапазон ревизий"
+// @Failure 404 {string} string "Ревизия не найдена"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/revisions/diff [get]
+func (h *Handler) DiffSongRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from, fromErr := strconv.Atoi(query.Get("from"))
+	to, toErr := strconv.Atoi(query.Get("to"))
+	if fromErr != nil || toErr != nil {
+		http.Error(w, "Параметры from и to должны быть номерами ревизий", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to compare revisions
+	diff, err := h.service.DiffSongRevisions(r.Context(), id, from, to)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// RevertSongRevisionHandler handles POST requests to revert song to its revision
+// @Summary Revert song to revision
+// @Description Restore group, title, release date, text and link of song from revision.
+// @Description Revert is stored as a new revision. Song in trash must be restored first
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param rev path int true "Revision number"
+// @Param If-Match header string false "ETag of song, revert is rejected with 412 if song was changed"
+// @Success 200 {object} models.Song "Reverted song"
+// @Header 200 {string} ETag "New version of song"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена или Ревизия не найдена"
+// @Failure 412 {string} string "Версия песни не совпадает с If-Match"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/revisions/{rev}/revert [post]
+func (h *Handler) RevertSongRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id, revision, ok := parseRevisionPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Get version of song required by If-Match header
+	version, err := h.expectedVersion(r, id)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	// Call service to revert song
+	song, err := h.service.RevertSongToRevision(r.Context(), id, revision, version)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	writeSong(w, http.StatusOK, song)
+}
+
+// parseRevisionPath parses song ID and revision number from path, responding with 400 if they are invalid
+func parseRevisionPath(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	revision, err := strconv.Atoi(vars["rev"])
+	if err != nil {
+		http.Error(w, "Неправильный формат номера ревизии", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, revision, true
+}
+
+// writeRevisionError maps error of revision operations to HTTP response
+func writeRevisionError(w http.ResponseWriter, err error) {
+	if writePreconditionError(w, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, api.ErrInvalidRevisionRange):
+		http.Error(w, "Неправильный диапазон ревизий: from и to должны быть разными номерами ревизий", http.StatusBadRequest)
+	case errors.Is(err, postgresql.ErrRevisionNotFound):
+		http.Error(w, "Ревизия не найдена", http.StatusNotFound)
+	case errors.Is(err, postgresql.ErrSongNotFound):
+		http.Error(w, "Песня не найдена", http.StatusNotFound)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
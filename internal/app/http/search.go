@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/models"
+)
+
+// SearchSongsHandler handles GET requests for full-text search over songs
+// @Summary Search songs
+// @Description Full-text search over song title, group and text with ranked results and highlighted snippets.
+// @Description Query supports web search syntax: "quoted phrase", OR, -excluded word
+// @Tags Songs
+// @Produce json
+// @Param q query string true "Search query"
+// @Param lang query string false "Text search language" Enums(ru, en)
+// @Param group query []string false "Filter by group, may be repeated to match any of groups" collectionFormat(multi)
+// @Param song query string false "Filter by song title"
+// @Param match query string false "Case-insensitive match mode of group and song filters" Enums(exact, prefix, contains) default(exact)
+// @Param release_date query string false "Filter by release date" Format("02.01.2006")
+// @Param release_from query string false "Filter by release date from (inclusive)" Format("02.01.2006")
+// @Param release_to query string false "Filter by release date to (inclusive)" Format("02.01.2006")
+// @Param created_after query string false "Filter songs created after timestamp" Format(date-time)
+// @Param updated_after query string false "Filter songs updated after timestamp" Format(date-time)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(10)
+// @Success 200 {array} models.SongSearchResult
+// @Failure 400 {string} string "Пустой поисковый запрос, Неправильный язык поиска, Неправильный формат параметра или Неправильный фильтр"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/search [get]
+func (h *Handler) SearchSongsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	search := models.SongSearch{
+		Query: query.Get("q"),
+		Lang:  query.Get("lang"),
+	}
+
+	// Parse filter parameters
+	filter, err := parseSongFilters(query)
+	if err != nil {
+		writeFilterError(w, err)
+		return
+	}
+
+	// Parse pagination parameters
+	page, pageSize := parsePagination(query)
+
+	// Call service to search songs
+	results, err := h.service.SearchSongs(r.Context(), search, filter, page, pageSize)
+	if err != nil {
+		// Return 400 error if filter is inconsistent
+		if writeFilterError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, api.ErrEmptySearchQuery):
+			http.Error(w, "Пустой поисковый запрос", http.StatusBadRequest)
+		case errors.Is(err, api.ErrInvalidSearchLang):
+			http.Error(w, "Неправильный язык поиска", http.StatusBadRequest)
+		default:
+			http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Respond with found songs
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"rest-songs/internal/app/api"
+	"rest-songs/internal/app/lyrics"
+	"rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// lrcContentType is content type of LRC documents
+const lrcContentType = "application/x-lrc"
+
+// GetSyncedLyricsHandler handles GET requests to retrieve time-synchronized text of song
+// @Summary Get synced lyrics
+// @Description Get lines of song text with offset of every line in milliseconds from the start of song
+// @Tags Lyrics
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} models.SyncedLyrics
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена или Синхронизированный текст не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/lyrics.json [get]
+func (h *Handler) GetSyncedLyricsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to get synced lyrics
+	synced, err := h.service.GetSyncedLyrics(r.Context(), id)
+	if err != nil {
+		writeSyncedLyricsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(synced)
+}
+
+// SetSyncedLyricsHandler handles PUT requests to set timings of song text lines
+// @Summary Set synced lyrics
+// @Description Set offset in milliseconds of every line of song text. Lines of body are matched to lines
+// @Description of text by their order, only offset_ms is used, so response of GET may be sent back as is.
+// @Description Every line must have its timing and timings must not decrease. Timings are removed when text changes
+// @Tags Lyrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of song, request is rejected with 412 if song was changed"
+// @Param lyrics body models.SyncedLyrics true "Timings of lines"
+// @Success 200 {object} models.SyncedLyrics
+// @Failure 400 {string} string "Неправильный формат ID или Неправильные тайминги"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 412 {string} string "Версия песни не совпадает с If-Match"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/lyrics.json [put]
+func (h *Handler) SetSyncedLyricsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	var body models.SyncedLyrics
+	if err = json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Неправильный формат JSON", http.StatusBadRequest)
+		return
+	}
+
+	// Get version of song required by If-Match header
+	version, err := h.expectedVersion(r, id)
+	if err != nil {
+		writeSyncedLyricsError(w, err)
+		return
+	}
+
+	offsets := make([]int, len(body.Lines))
+	for i, line := range body.Lines {
+		offsets[i] = line.OffsetMs
+	}
+
+	// Call service to set timings
+	synced, err := h.service.SetSyncedLyrics(r.Context(), id, offsets, version)
+	if err != nil {
+		writeSyncedLyricsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(synced)
+}
+
+// ExportLRCHandler handles GET requests to export time-synchronized text of song as LRC
+// @Summary Export LRC
+// @Description Get synced lyrics of song as LRC document with artist and title tags
+// @Tags Lyrics
+// @Produce plain
+// @Param id path int true "Song ID"
+// @Success 200 {string} string "LRC document"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена или Синхронизированный текст не найден"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/lyrics.lrc [get]
+func (h *Handler) ExportLRCHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to get synced lyrics
+	synced, err := h.service.GetSyncedLyrics(r.Context(), id)
+	if err != nil {
+		writeSyncedLyricsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", lrcContentType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="song-`+strconv.Itoa(id)+`.lrc"`)
+	if err = lyrics.FormatLRC(w, synced); err != nil {
+		h.logger.Errorf("ExportLRCHandler[handler]: Ошибка записи LRC песни ID %d: %v", id, err)
+	}
+}
+
+// ImportLRCHandler handles PUT requests to set timings of song text lines from LRC document
+// @Summary Import LRC
+// @Description Set synced lyrics of song from LRC document. Timed lines of document are matched to lines
+// @Description of song text by their order, so their count must be equal. Timings must not decrease,
+// @Description unless lines have several time tags. ID tags are ignored except [offset:]
+// @Tags Lyrics
+// @Accept plain
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-Match header string false "ETag of song, request is rejected with 412 if song was changed"
+// @Param body body string true "LRC document"
+// @Success 200 {object} models.SyncedLyrics
+// @Failure 400 {string} string "Неправильный формат ID или Неправильный LRC или Неправильные тайминги"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 412 {string} string "Версия песни не совпадает с If-Match"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/lyrics.lrc [put]
+func (h *Handler) ImportLRCHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Get version of song required by If-Match header
+	version, err := h.expectedVersion(r, id)
+	if err != nil {
+		writeSyncedLyricsError(w, err)
+		return
+	}
+
+	// Call service to import LRC
+	synced, err := h.service.ImportSongLRC(r.Context(), id, r.Body, version)
+	if err != nil {
+		writeSyncedLyricsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(synced)
+}
+
+// writeSyncedLyricsError maps error of synced lyrics operations to HTTP response
+func writeSyncedLyricsError(w http.ResponseWriter, err error) {
+	if writePreconditionError(w, err) {
+		return
+	}
+
+	var lrcErr *lyrics.LRCError
+	var syncErr *api.SyncError
+	switch {
+	case errors.As(err, &lrcErr):
+		http.Error(w, "Неправильный LRC: "+lrcErr.Error(), http.StatusBadRequest)
+	case errors.As(err, &syncErr):
+		http.Error(w, "Неправильные тайминги: "+syncErr.Reason, http.StatusBadRequest)
+	case errors.Is(err, api.ErrSyncedLyricsNotFound):
+		http.Error(w, "Синхронизированный текст не найден", http.StatusNotFound)
+	case errors.Is(err, postgresql.ErrSongNotFound):
+		http.Error(w, "Песня не найдена", http.StatusNotFound)
+	default:
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+	}
+}
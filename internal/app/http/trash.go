@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	// models is referenced only by swag annotations, which resolve types through imports of file
+	_ "rest-songs/internal/app/models"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// GetDeletedSongsHandler handles GET request for retrieving songs in trash
+// @Summary Get songs in trash
+// @Description Get deleted songs, most recently deleted first. Songs are kept in trash for retention period
+// @Description and may be restored until then
+// @Tags Trash
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(10)
+// @Success 200 {object} models.Page[models.Song] "Page of deleted songs"
+// @Header 200 {string} Link "Links to first, prev, next and last pages"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/trash [get]
+func (h *Handler) GetDeletedSongsHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination parameters
+	page, pageSize := parsePagination(r.URL.Query())
+
+	// Call service to get songs in trash
+	songs, err := h.service.GetDeletedSongs(r.Context(), page, pageSize)
+	if err != nil {
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
+	}
+
+	// Respond with page of deleted songs
+	setPageLinks(w, r, songs)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(songs)
+}
+
+// RestoreSongByIdHandler handles POST requests to restore a song from trash by its ID
+// @Summary Restore song from trash
+// @Tags Trash
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} models.Song "Restored song"
+// @Failure 400 {string} string "Неправильный формат ID"
+// @Failure 404 {string} string "Песня не найдена в корзине"
+// @Failure 500 {string} string "Проблема на сервере"
+// @Router /songs/{id}/restore [post]
+func (h *Handler) RestoreSongByIdHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Неправильный формат ID", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to restore song
+	song, err := h.service.RestoreSongById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgresql.ErrSongNotFound) {
+			http.Error(w, "Песня не найдена в корзине", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Проблема на сервере", http.StatusInternalServerError)
+		return
+	}
+
+	// Respond with restored song
+	writeSong(w, http.StatusOK, song)
+}
@@ -0,0 +1,162 @@
+package lyrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+// lrcTimeRegexp matches LRC time tag like "[01:23.45]", "[01:23.456]" or "[01:23]" at the beginning of line
+var lrcTimeRegexp = regexp.MustCompile(`^\[(\d+):([0-5]?\d)(?:[.:](\d{1,3}))?\]`)
+
+// maxLRCLineBytes limits length of single line of LRC document
+const maxLRCLineBytes = 1 << 20
+
+// lrcTagRegexp matches LRC ID tag like "[ar:Imagine Dragons]" or "[offset:+500]"
+var lrcTagRegexp = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+
+// LRCLine represents timed line of LRC document
+type LRCLine struct {
+	OffsetMs int
+	Text     string
+}
+
+// LRCError represents error in LRC document with number of line it was found on
+type LRCError struct {
+	Line   int
+	Reason string
+}
+
+func (e *LRCError) Error() string {
+	return fmt.Sprintf("строка %d: %s", e.Line, e.Reason)
+}
+
+// ParseLRC reads LRC document and returns its timed lines ordered by time
+// ID tags are skipped except [offset:], which shifts all timings. Time tags with empty text
+// mark instrumental breaks and are skipped too. Line with several time tags is sung several times,
+// so lines are sorted by time in such documents, otherwise timings must not decrease through document
+func ParseLRC(r io.Reader) ([]LRCLine, error) {
+	var lines []LRCLine
+	var sources []int
+	offset := 0
+	compressed := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLRCLineBytes)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if number == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if line == "" {
+			continue
+		}
+
+		var times []int
+		for {
+			match := lrcTimeRegexp.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			t, ok := lrcTime(match[1], match[2], match[3])
+			if !ok {
+				return nil, &LRCError{Line: number, Reason: "время превышает максимально допустимое"}
+			}
+			times = append(times, t)
+			line = strings.TrimSpace(line[len(match[0]):])
+		}
+
+		if len(times) == 0 {
+			tag := lrcTagRegexp.FindStringSubmatch(line)
+			if tag == nil {
+				return nil, &LRCError{Line: number, Reason: "ожидается тег времени [мм:сс.xx]"}
+			}
+			if strings.EqualFold(tag[1], "offset") {
+				value, err := strconv.Atoi(strings.TrimSpace(tag[2]))
+				if err != nil || value > models.MaxOffsetMs || value < -models.MaxOffsetMs {
+					return nil, &LRCError{Line: number, Reason: "некорректное значение offset"}
+				}
+				offset = value
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		if len(times) > 1 {
+			compressed = true
+		}
+		for _, t := range times {
+			lines = append(lines, LRCLine{OffsetMs: t, Text: line})
+			sources = append(sources, number)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, &LRCError{Line: number + 1, Reason: fmt.Sprintf("строка длиннее %d байт", maxLRCLineBytes)}
+		}
+		return nil, err
+	}
+
+	if compressed {
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].OffsetMs < lines[j].OffsetMs })
+	} else {
+		for i := 1; i < len(lines); i++ {
+			if lines[i].OffsetMs < lines[i-1].OffsetMs {
+				return nil, &LRCError{Line: sources[i], Reason: "время строки меньше времени предыдущей строки"}
+			}
+		}
+	}
+
+	// Positive offset means that lyrics should be shown earlier
+	for i := range lines {
+		lines[i].OffsetMs -= offset
+		if lines[i].OffsetMs < 0 {
+			lines[i].OffsetMs = 0
+		}
+	}
+	return lines, nil
+}
+
+// lrcTime converts minutes, seconds and fraction of second of LRC time tag to milliseconds
+// Fraction has precision of its digits count: tenths, hundredths or thousandths.
+// It reports false if time is later than MaxOffsetMs
+func lrcTime(minutes, seconds, fraction string) (int, bool) {
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m > models.MaxOffsetMs/60000 {
+		return 0, false
+	}
+	s, _ := strconv.Atoi(seconds)
+	ms := 0
+	if fraction != "" {
+		ms, _ = strconv.Atoi(fraction + strings.Repeat("0", 3-len(fraction)))
+	}
+	t := (m*60+s)*1000 + ms
+	return t, t <= models.MaxOffsetMs
+}
+
+// FormatLRC writes synced lyrics as LRC document with artist and title tags
+func FormatLRC(w io.Writer, synced models.SyncedLyrics) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "[ar:%s]\n[ti:%s]\n", lrcTagValue(synced.Group), lrcTagValue(synced.Title))
+	for _, line := range synced.Lines {
+		ms := line.OffsetMs
+		fmt.Fprintf(bw, "[%02d:%02d.%02d]%s\n", ms/60000, ms/1000%60, ms%1000/10, line.Text)
+	}
+	return bw.Flush()
+}
+
+// lrcTagValue removes characters that would break ID tag
+func lrcTagValue(value string) string {
+	return strings.NewReplacer("]", "", "\n", " ").Replace(value)
+}
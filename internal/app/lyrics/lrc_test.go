@@ -0,0 +1,123 @@
+package lyrics
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rest-songs/internal/app/models"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []LRCLine
+		wantLine int
+	}{
+		{name: "empty", document: "", want: nil},
+		{
+			name:     "time precisions",
+			document: "[00:01]a\n[00:02.5]b\n[00:03.25]c\n[00:04.125]d\n[1:05:50]e",
+			want:     []LRCLine{{1000, "a"}, {2500, "b"}, {3250, "c"}, {4125, "d"}, {65500, "e"}},
+		},
+		{
+			name:     "ID tags and blank lines are skipped",
+			document: "\uFEFF[ar:Imagine Dragons]\n[ti:Believer]\n\n[length: 03:24]\n[00:10.00]First things first\n",
+			want:     []LRCLine{{10000, "First things first"}},
+		},
+		{
+			name:     "CRLF and spaces",
+			document: "[00:01.00]  a  \r\n[00:02.00] b\r\n",
+			want:     []LRCLine{{1000, "a"}, {2000, "b"}},
+		},
+		{
+			name:     "instrumental breaks are skipped",
+			document: "[00:01.00]a\n[00:05.00]\n[00:06.00]b",
+			want:     []LRCLine{{1000, "a"}, {6000, "b"}},
+		},
+		{
+			name:     "equal timings",
+			document: "[00:01.00]a\n[00:01.00]b",
+			want:     []LRCLine{{1000, "a"}, {1000, "b"}},
+		},
+		{
+			name:     "several time tags",
+			document: "[00:01.00][00:20.00]chorus\n[00:10.00]verse",
+			want:     []LRCLine{{1000, "chorus"}, {10000, "verse"}, {20000, "chorus"}},
+		},
+		{
+			name:     "positive offset",
+			document: "[offset:+500]\n[00:00.30]a\n[00:01.00]b",
+			want:     []LRCLine{{0, "a"}, {500, "b"}},
+		},
+		{
+			name:     "negative offset",
+			document: "[offset:-250]\n[00:01.00]a",
+			want:     []LRCLine{{1250, "a"}},
+		},
+		{name: "latest time", document: "[35791:23.647]a", want: []LRCLine{{models.MaxOffsetMs, "a"}}},
+		{name: "line without time tag", document: "[00:01.00]a\nb", wantLine: 2},
+		{name: "decreasing timings", document: "[00:02.00]a\n\n[00:01.00]b", wantLine: 3},
+		{name: "seconds out of range", document: "[00:60.00]a", wantLine: 1},
+		{name: "time out of range", document: "[00:01.00]a\n[35791:23.648]b", wantLine: 2},
+		{name: "minutes out of range", document: "[99999999999999999999:00.00]a", wantLine: 1},
+		{name: "invalid offset", document: "[offset:soon]\n[00:01.00]a", wantLine: 1},
+		{name: "offset out of range", document: "[offset:3000000000]\n[00:01.00]a", wantLine: 1},
+		{name: "line too long", document: "[00:01.00]a\n[00:02.00]" + strings.Repeat("b", maxLRCLineBytes), wantLine: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := ParseLRC(strings.NewReader(tt.document))
+			if tt.wantLine != 0 {
+				var lrcErr *LRCError
+				if !errors.As(err, &lrcErr) {
+					t.Fatalf("ParseLRC() error = %v, want LRCError", err)
+				}
+				if lrcErr.Line != tt.wantLine {
+					t.Fatalf("ParseLRC() error on line %d, want %d: %v", lrcErr.Line, tt.wantLine, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLRC() error = %v", err)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Fatalf("ParseLRC() = %+v, want %+v", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLRC(t *testing.T) {
+	synced := models.SyncedLyrics{
+		Group: "Imagine [Dragons]",
+		Title: "Believer\nLive",
+		Lines: []models.SyncedLine{
+			{Number: 1, Verse: 1, Text: "First things first", OffsetMs: 1234},
+			{Number: 2, Verse: 1, Text: "I'ma say all the words", OffsetMs: 3723456},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatLRC(&buf, synced); err != nil {
+		t.Fatalf("FormatLRC() error = %v", err)
+	}
+	want := "[ar:Imagine [Dragons]\n[ti:Believer Live]\n[00:01.23]First things first\n[62:03.45]I'ma say all the words\n"
+	if buf.String() != want {
+		t.Fatalf("FormatLRC() = %q, want %q", buf.String(), want)
+	}
+
+	// Formatted document is parsed back with precision of hundredths
+	lines, err := ParseLRC(&buf)
+	if err != nil {
+		t.Fatalf("ParseLRC() of formatted document error = %v", err)
+	}
+	wantLines := []LRCLine{{1230, "First things first"}, {3723450, "I'ma say all the words"}}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Fatalf("ParseLRC() of formatted document = %+v, want %+v", lines, wantLines)
+	}
+}
@@ -0,0 +1,195 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"rest-songs/internal/app/models"
+)
+
+// markerRegexp matches section marker line like "[Chorus]", "[Verse 2]" or "[Chorus: Dan Reynolds]"
+var markerRegexp = regexp.MustCompile(`^\[\s*([^\]:]+?)\s*(?::[^\]]*)?\]$`)
+
+// sectionNames maps first word of marker to section type, both English and Russian names are known
+var sectionNames = map[string]string{
+	"verse":      models.SectionVerse,
+	"куплет":     models.SectionVerse,
+	"chorus":     models.SectionChorus,
+	"refrain":    models.SectionChorus,
+	"припев":     models.SectionChorus,
+	"pre-chorus": models.SectionPreChorus,
+	"prechorus":  models.SectionPreChorus,
+	"предприпев": models.SectionPreChorus,
+	"bridge":     models.SectionBridge,
+	"бридж":      models.SectionBridge,
+	"intro":      models.SectionIntro,
+	"вступление": models.SectionIntro,
+	"outro":      models.SectionOutro,
+	"концовка":   models.SectionOutro,
+	"hook":       models.SectionHook,
+	"хук":        models.SectionHook,
+}
+
+// Normalize converts CRLF and CR line endings to LF and removes trailing whitespace of every line
+func Normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse splits song text into verses separated by one or more blank lines or by section markers
+// Blank lines at the beginning and end of text are ignored. Lines are numbered through whole text
+// without blank lines and markers, so numbers don't depend on how verses are separated.
+// Sections and repeats are detected as described in detectSections
+func Parse(text string) []models.Verse {
+	verses := []models.Verse{}
+	var marked []bool
+	var current *models.Verse
+	number := 0
+
+	for _, line := range strings.Split(Normalize(text), "\n") {
+		if match := markerRegexp.FindStringSubmatch(strings.TrimSpace(line)); match != nil && strings.TrimSpace(match[1]) != "" {
+			verses = append(verses, models.Verse{
+				Index:   len(verses) + 1,
+				Section: markerSection(match[1]),
+				Label:   match[1],
+				Lines:   []models.LyricsLine{},
+			})
+			marked = append(marked, true)
+			current = &verses[len(verses)-1]
+			continue
+		}
+
+		if line == "" {
+			// Blank lines right after marker don't separate it from its lines
+			if current != nil && len(current.Lines) > 0 {
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			verses = append(verses, models.Verse{Index: len(verses) + 1, Section: models.SectionVerse})
+			marked = append(marked, false)
+			current = &verses[len(verses)-1]
+		}
+		number++
+		current.Lines = append(current.Lines, models.LyricsLine{Number: number, Verse: current.Index, Text: line})
+	}
+
+	detectSections(verses, marked)
+	return verses
+}
+
+// detectSections links repeated verses to their first occurrence
+// Verses with the same lines, ignoring case and punctuation, are repeats. Unmarked verse repeated
+// anywhere in text is considered chorus. Marker without lines, like lone "[Chorus]", repeats the last verse
+// with the same label, or else with the same section
+func detectSections(verses []models.Verse, marked []bool) {
+	first := make(map[string]int)
+	repeated := make(map[int]bool)
+
+	for i := range verses {
+		verse := &verses[i]
+
+		if len(verse.Lines) == 0 {
+			if ref := lastMarkedLike(verses[:i], verse); ref != 0 {
+				verse.RepeatOf = &ref
+			}
+			continue
+		}
+
+		key := verseKey(verse.Lines)
+		index, ok := first[key]
+		if !ok {
+			first[key] = verse.Index
+			continue
+		}
+
+		verse.RepeatOf = &index
+		repeated[index] = true
+		if !marked[i] {
+			verse.Section = verses[index-1].Section
+		}
+	}
+
+	for i := range verses {
+		if marked[i] {
+			continue
+		}
+		if repeated[verses[i].Index] || (verses[i].RepeatOf != nil && !marked[*verses[i].RepeatOf-1]) {
+			verses[i].Section = models.SectionChorus
+		}
+	}
+}
+
+// lastMarkedLike finds the last verse with lines which has the same label as verse, or else the same section
+// It returns index of found verse or 0
+func lastMarkedLike(verses []models.Verse, verse *models.Verse) int {
+	bySection := 0
+	for i := len(verses) - 1; i >= 0; i-- {
+		if len(verses[i].Lines) == 0 {
+			continue
+		}
+		if strings.EqualFold(verses[i].Label, verse.Label) {
+			return verses[i].Index
+		}
+		if bySection == 0 && verses[i].Section == verse.Section {
+			bySection = verses[i].Index
+		}
+	}
+	return bySection
+}
+
+// markerSection returns section type by name from marker, e.g. "Verse 2" is verse
+func markerSection(name string) string {
+	word := strings.ToLower(strings.Fields(name)[0])
+	if section, ok := sectionNames[word]; ok {
+		return section
+	}
+	if strings.HasPrefix(word, "pre") && strings.Contains(word, "chorus") {
+		return models.SectionPreChorus
+	}
+	return models.SectionOther
+}
+
+// verseKey returns text of verse lines in lower case without punctuation and extra spaces,
+// so that "Pain!" and "pain" are the same
+func verseKey(lines []models.LyricsLine) string {
+	var key strings.Builder
+	for _, line := range lines {
+		words := strings.FieldsFunc(strings.ToLower(line.Text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+		})
+		key.WriteString(strings.Join(words, " "))
+		key.WriteByte('\n')
+	}
+	return key.String()
+}
+
+// Collapse returns copy of verses where repeated verses have no lines and are only referenced by RepeatOf
+func Collapse(verses []models.Verse) []models.Verse {
+	collapsed := make([]models.Verse, len(verses))
+	for i, verse := range verses {
+		if verse.RepeatOf != nil {
+			verse.Lines = []models.LyricsLine{}
+		}
+		collapsed[i] = verse
+	}
+	return collapsed
+}
+
+// Lines returns all lines of parsed verses in order
+func Lines(verses []models.Verse) []models.LyricsLine {
+	lines := []models.LyricsLine{}
+	for _, verse := range verses {
+		lines = append(lines, verse.Lines...)
+	}
+	return lines
+}
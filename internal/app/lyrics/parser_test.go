@@ -0,0 +1,223 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+
+	"rest-songs/internal/app/models"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "LF", text: "a\nb", want: "a\nb"},
+		{name: "CRLF", text: "a\r\nb\r\n", want: "a\nb\n"},
+		{name: "CR", text: "a\rb", want: "a\nb"},
+		{name: "trailing spaces", text: "a  \t\nb \r\n", want: "a\nb\n"},
+		{name: "leading spaces are kept", text: "  a", want: "  a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Fatalf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want [][]string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "blank lines only", text: "\n \n\t\n", want: nil},
+		{name: "single verse", text: "a\nb", want: [][]string{{"a", "b"}}},
+		{name: "verses", text: "a\nb\n\nc", want: [][]string{{"a", "b"}, {"c"}}},
+		{name: "several blank lines", text: "a\n\n\n\nb", want: [][]string{{"a"}, {"b"}}},
+		{name: "whitespace line separates verses", text: "a\n   \nb", want: [][]string{{"a"}, {"b"}}},
+		{name: "blank lines around text", text: "\n\na\n\nb\n\n", want: [][]string{{"a"}, {"b"}}},
+		{name: "CRLF", text: "a\r\nb\r\n\r\nc\r\n", want: [][]string{{"a", "b"}, {"c"}}},
+		{name: "leading spaces are kept", text: "  a\nb  ", want: [][]string{{"  a", "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verses := Parse(tt.text)
+			if verses == nil {
+				t.Fatal("Parse() = nil, want empty slice")
+			}
+
+			var got [][]string
+			number := 0
+			for i, verse := range verses {
+				if verse.Index != i+1 {
+					t.Fatalf("verse %d has index %d", i+1, verse.Index)
+				}
+				var texts []string
+				for _, line := range verse.Lines {
+					number++
+					if line.Number != number || line.Verse != verse.Index {
+						t.Fatalf("line %q is %d of verse %d, want %d of verse %d", line.Text, line.Number, line.Verse, number, verse.Index)
+					}
+					texts = append(texts, line.Text)
+				}
+				got = append(got, texts)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse() verses = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	lines := Lines(Parse("a\nb\n\nc"))
+	want := []models.LyricsLine{{Number: 1, Verse: 1, Text: "a"}, {Number: 2, Verse: 1, Text: "b"}, {Number: 3, Verse: 2, Text: "c"}}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("Lines() = %+v, want %+v", lines, want)
+	}
+
+	if lines = Lines(Parse("")); lines == nil || len(lines) != 0 {
+		t.Fatalf("Lines() of empty text = %#v, want empty slice", lines)
+	}
+}
+
+// sectionWant describes expected verse of TestParseSections, RepeatOf 0 means verse is not a repeat
+type sectionWant struct {
+	Section  string
+	Label    string
+	RepeatOf int
+	Lines    int
+}
+
+func TestParseSections(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []sectionWant
+	}{
+		{
+			name: "markers",
+			text: "[Intro]\na\n\n[Verse 1]\nb\n\n[Pre-Chorus]\nc\n[Chorus: Dan Reynolds]\nd\n\n[Bridge]\ne\n\n[Outro]\nf\n\n[Hook]\ng\n\n[Solo]\nh",
+			want: []sectionWant{
+				{Section: models.SectionIntro, Label: "Intro", Lines: 1},
+				{Section: models.SectionVerse, Label: "Verse 1", Lines: 1},
+				{Section: models.SectionPreChorus, Label: "Pre-Chorus", Lines: 1},
+				{Section: models.SectionChorus, Label: "Chorus", Lines: 1},
+				{Section: models.SectionBridge, Label: "Bridge", Lines: 1},
+				{Section: models.SectionOutro, Label: "Outro", Lines: 1},
+				{Section: models.SectionHook, Label: "Hook", Lines: 1},
+				{Section: models.SectionOther, Label: "Solo", Lines: 1},
+			},
+		},
+		{
+			name: "russian markers",
+			text: "[Куплет 1]\na\n\n[Припев]\nb\n\n[Бридж]\nc",
+			want: []sectionWant{
+				{Section: models.SectionVerse, Label: "Куплет 1", Lines: 1},
+				{Section: models.SectionChorus, Label: "Припев", Lines: 1},
+				{Section: models.SectionBridge, Label: "Бридж", Lines: 1},
+			},
+		},
+		{
+			name: "blank line after marker",
+			text: "[Chorus]\n\na\nb",
+			want: []sectionWant{{Section: models.SectionChorus, Label: "Chorus", Lines: 2}},
+		},
+		{
+			name: "empty brackets are text",
+			text: "[ ]\na",
+			want: []sectionWant{{Section: models.SectionVerse, Lines: 2}},
+		},
+		{
+			name: "unmarked repeat is chorus",
+			text: "a\n\nb\nc\n\nd\n\nB\nc!",
+			want: []sectionWant{
+				{Section: models.SectionVerse, Lines: 1},
+				{Section: models.SectionChorus, Lines: 2},
+				{Section: models.SectionVerse, Lines: 1},
+				{Section: models.SectionChorus, RepeatOf: 2, Lines: 2},
+			},
+		},
+		{
+			name: "unmarked repeat of marked verse keeps its section",
+			text: "[Bridge]\na\n\nb\n\na",
+			want: []sectionWant{
+				{Section: models.SectionBridge, Label: "Bridge", Lines: 1},
+				{Section: models.SectionVerse, Lines: 1},
+				{Section: models.SectionBridge, RepeatOf: 1, Lines: 1},
+			},
+		},
+		{
+			name: "marked repeat keeps its marker",
+			text: "a\n\n[Outro]\na",
+			want: []sectionWant{
+				{Section: models.SectionChorus, Lines: 1},
+				{Section: models.SectionOutro, Label: "Outro", RepeatOf: 1, Lines: 1},
+			},
+		},
+		{
+			name: "lone marker repeats verse with the same label",
+			text: "[Chorus]\na\n\n[Verse]\nb\n\n[Chorus]\n\n[Verse]\nc",
+			want: []sectionWant{
+				{Section: models.SectionChorus, Label: "Chorus", Lines: 1},
+				{Section: models.SectionVerse, Label: "Verse", Lines: 1},
+				{Section: models.SectionChorus, Label: "Chorus", RepeatOf: 1},
+				{Section: models.SectionVerse, Label: "Verse", Lines: 1},
+			},
+		},
+		{
+			name: "lone marker repeats verse with the same section",
+			text: "[Chorus 1]\na\n\n[Refrain]",
+			want: []sectionWant{
+				{Section: models.SectionChorus, Label: "Chorus 1", Lines: 1},
+				{Section: models.SectionChorus, Label: "Refrain", RepeatOf: 1},
+			},
+		},
+		{
+			name: "lone marker without match",
+			text: "[Verse]\na\n\n[Chorus]",
+			want: []sectionWant{
+				{Section: models.SectionVerse, Label: "Verse", Lines: 1},
+				{Section: models.SectionChorus, Label: "Chorus"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []sectionWant
+			for _, verse := range Parse(tt.text) {
+				v := sectionWant{Section: verse.Section, Label: verse.Label, Lines: len(verse.Lines)}
+				if verse.RepeatOf != nil {
+					v.RepeatOf = *verse.RepeatOf
+				}
+				got = append(got, v)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	verses := Parse("a\n\nb\n\na")
+	collapsed := Collapse(verses)
+
+	if len(collapsed[0].Lines) != 1 || len(collapsed[1].Lines) != 1 {
+		t.Fatalf("Collapse() removed lines of verses which are not repeats: %+v", collapsed)
+	}
+	if len(collapsed[2].Lines) != 0 || collapsed[2].RepeatOf == nil || *collapsed[2].RepeatOf != 1 {
+		t.Fatalf("Collapse() kept lines of repeat: %+v", collapsed[2])
+	}
+	if len(verses[2].Lines) != 1 {
+		t.Fatal("Collapse() modified given verses")
+	}
+}
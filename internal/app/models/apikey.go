@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Roles of API clients, each role is allowed everything the previous one is
+// Readers can only read, editors can also create and update, admins can also delete and manage keys
+const (
+	RoleReader = "reader"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// APIKey represents key of API client, the key itself is stored only as hash
+// Prefix is the beginning of the key, which is shown to tell keys apart
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// IssuedAPIKey represents newly issued API key, Key is returned only once
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
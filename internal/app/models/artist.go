@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Artist represents group (performer) of songs in library
+type Artist struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	SongsCount int       `json:"songs_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ArtistRequest struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// Notations of chord names
+const (
+	NotationLatin     = "latin"
+	NotationGerman    = "german"
+	NotationNashville = "nashville"
+)
+
+// Kinds of chord sheet lines
+const (
+	ChordLineLyrics    = "lyrics"
+	ChordLineDirective = "directive"
+	ChordLineComment   = "comment"
+	ChordLineEmpty     = "empty"
+)
+
+// ChordOptions holds options of chord sheet rendering
+// Transpose is number of semitones to shift chords by, from -11 to 11
+type ChordOptions struct {
+	Transpose int
+	Notation  string
+}
+
+// ChordAnchor represents chord placed above lyrics, Position is 0-based index of character it is sung on
+type ChordAnchor struct {
+	Position int    `json:"position"`
+	Chord    string `json:"chord"`
+}
+
+// ChordLine represents line of ChordPro document, Number is 1-based number of line in document
+// Lyrics lines hold text without chords and chords anchored to it. Directive lines hold
+// name of directive and its value in Text. Section is set for lines between {start_of_...} and {end_of_...}
+type ChordLine struct {
+	Number    int           `json:"number"`
+	Kind      string        `json:"kind"`
+	Section   string        `json:"section,omitempty"`
+	Directive string        `json:"directive,omitempty"`
+	Text      string        `json:"text"`
+	Chords    []ChordAnchor `json:"chords,omitempty"`
+}
+
+// ChordSheet represents parsed ChordPro document of song
+// Metadata holds values of directives like {title}, {artist} and {key}, Key is key of song after transposition
+type ChordSheet struct {
+	SongID    int               `json:"song_id"`
+	Key       string            `json:"key,omitempty"`
+	Transpose int               `json:"transpose"`
+	Notation  string            `json:"notation"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Lines     []ChordLine       `json:"lines"`
+}
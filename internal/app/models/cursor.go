@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points to boundary row of page in ordered list of songs
+// Keys hold text values of row sort keys, the last of them is always song ID, and Sort is order they belong to.
+// Backward cursor addresses page preceding boundary row, forward one - page following it.
+// In JSON and query parameters cursor is represented as opaque base64 string
+type Cursor struct {
+	Keys     []string `json:"k"`
+	Sort     string   `json:"s,omitempty"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// cursorData is Cursor without text marshalling methods, used to encode cursor contents
+type cursorData Cursor
+
+// MarshalText encodes cursor into opaque string
+func (c Cursor) MarshalText() ([]byte, error) {
+	data, err := json.Marshal(cursorData(c))
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([]byte, base64.RawURLEncoding.EncodedLen(len(data)))
+	base64.RawURLEncoding.Encode(encoded, data)
+	return encoded, nil
+}
+
+// UnmarshalText decodes cursor from opaque string, returning ErrInvalidCursor if it is malformed
+func (c *Cursor) UnmarshalText(text []byte) error {
+	data := make([]byte, base64.RawURLEncoding.DecodedLen(len(text)))
+	n, err := base64.RawURLEncoding.Decode(data, text)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	var decoded cursorData
+	if err = json.Unmarshal(data[:n], &decoded); err != nil || len(decoded.Keys) == 0 {
+		return ErrInvalidCursor
+	}
+
+	*c = Cursor(decoded)
+	return nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "default sort", cursor: Cursor{Keys: []string{"2024-10-01 00:00:00+00", "42"}}},
+		{name: "backward", cursor: Cursor{Keys: []string{"7"}, Sort: "id", Backward: true}},
+		{name: "text keys", cursor: Cursor{Keys: []string{"Сплин", "Мороз по коже", "3"}, Sort: "group,-song@ru"}},
+		{name: "special characters", cursor: Cursor{Keys: []string{"a\"b\\c/+=&?", "", "1"}, Sort: "-group,song"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.cursor.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+			for _, c := range text {
+				if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+					t.Fatalf("MarshalText() = %q, want URL safe string", text)
+				}
+			}
+
+			var got Cursor
+			if err = got.UnmarshalText(text); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Fatalf("UnmarshalText() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestCursorInJSON(t *testing.T) {
+	page := struct {
+		Next *Cursor `json:"next"`
+	}{Next: &Cursor{Keys: []string{"5"}, Sort: "id"}}
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if _, ok := fields["next"].(string); !ok {
+		t.Fatalf("cursor is encoded as %v, want opaque string", fields["next"])
+	}
+}
+
+func TestCursorUnmarshalInvalid(t *testing.T) {
+	encode := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "empty", text: ""},
+		{name: "not base64", text: "not a cursor!"},
+		{name: "padded base64", text: base64.URLEncoding.EncodeToString([]byte(`{"k":["1"]}`))},
+		{name: "not JSON", text: encode("cursor")},
+		{name: "wrong types", text: encode(`{"k":[1]}`)},
+		{name: "without keys", text: encode(`{"s":"id"}`)},
+		{name: "empty keys", text: encode(`{"k":[]}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Cursor
+			if err := c.UnmarshalText([]byte(tt.text)); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("UnmarshalText() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
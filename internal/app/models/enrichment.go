@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Statuses of enrichment job
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobDead    = "dead"
+)
+
+// EnrichmentJob represents queued request to fetch song details from external API
+type EnrichmentJob struct {
+	ID       int    `json:"id"`
+	SongID   int    `json:"song_id"`
+	Group    string `json:"group"`
+	Title    string `json:"song"`
+	Attempts int    `json:"attempts"`
+}
+
+// SongEnrichment describes enrichment state of song
+type SongEnrichment struct {
+	SongID        int        `json:"song_id"`
+	Status        string     `json:"status"`
+	JobStatus     string     `json:"job_status,omitempty"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
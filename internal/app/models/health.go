@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Statuses of health checks and of service as a whole
+// Degraded service is still ready, only its optional dependencies fail
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthFail     = "fail"
+)
+
+// HealthCheck represents result of checking single dependency
+type HealthCheck struct {
+	Status    string    `json:"status"`
+	Required  bool      `json:"required"`
+	Error     string    `json:"error,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthReport represents readiness of service with breakdown by dependency
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
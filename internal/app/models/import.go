@@ -0,0 +1,58 @@
+package models
+
+// Formats of song import and export
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatJSON   = "json"
+)
+
+// Import modes: insert always adds new songs, upsert updates song with the same group and title if it exists
+const (
+	ImportInsert = "insert"
+	ImportUpsert = "upsert"
+)
+
+// Statuses of imported rows
+const (
+	ImportAccepted = "accepted"
+	ImportRejected = "rejected"
+)
+
+// ImportOptions holds settings of bulk import
+// With DryRun set rows are loaded in transaction which is rolled back, so report shows what would be changed
+type ImportOptions struct {
+	Format string
+	Mode   string
+	DryRun bool
+}
+
+// ImportRecord represents song record of import file, fields are validated before loading
+type ImportRecord struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"release_date"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// ImportRow represents result of import of single row. Line is number of line where row starts
+type ImportRow struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	Group  string `json:"group,omitempty"`
+	Song   string `json:"song,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportReport represents result of bulk import
+type ImportReport struct {
+	Mode     string      `json:"mode"`
+	DryRun   bool        `json:"dry_run"`
+	Total    int         `json:"total"`
+	Accepted int         `json:"accepted"`
+	Rejected int         `json:"rejected"`
+	Created  int64       `json:"created"`
+	Updated  int64       `json:"updated"`
+	Rows     []ImportRow `json:"rows"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Units of song text pagination
+const (
+	TextUnitVerse = "verse"
+	TextUnitLine  = "line"
+)
+
+// Section types of verses
+const (
+	SectionVerse     = "verse"
+	SectionChorus    = "chorus"
+	SectionPreChorus = "pre_chorus"
+	SectionBridge    = "bridge"
+	SectionIntro     = "intro"
+	SectionOutro     = "outro"
+	SectionHook      = "hook"
+	SectionOther     = "other"
+)
+
+// TextOptions holds options of song text retrieval
+// Non-zero AsOf gives text as it was at that moment. With CollapseRepeats lines of repeated verses are omitted,
+// so they are only referenced by RepeatOf
+type TextOptions struct {
+	AsOf            time.Time
+	CollapseRepeats bool
+}
+
+// LyricsLine represents line of song text
+// Number is 1-based number of line within whole text, Verse is index of verse it belongs to
+type LyricsLine struct {
+	Number int    `json:"number"`
+	Verse  int    `json:"verse"`
+	Text   string `json:"text"`
+}
+
+// Verse represents verse (stanza) of song text, Index is 1-based
+// Section is taken from explicit marker like [Chorus], which is kept in Label, or detected heuristically.
+// RepeatOf is index of the first verse repeated by this one
+type Verse struct {
+	Index    int          `json:"index"`
+	Section  string       `json:"section"`
+	Label    string       `json:"label,omitempty"`
+	RepeatOf *int         `json:"repeat_of,omitempty"`
+	Lines    []LyricsLine `json:"lines"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// Count modes of paginated lists
+// Estimated count is taken from query planner statistics, which is much cheaper for large tables
+const (
+	CountExact     = "exact"
+	CountEstimated = "estimated"
+	CountNone      = "none"
+)
+
+// Page represents page of paginated list
+// Page is set in offset mode, NextCursor and PrevCursor in cursor mode.
+// Total and TotalPages are omitted when count is not requested
+type Page[T any] struct {
+	Items          []T     `json:"items"`
+	Page           int     `json:"page,omitempty"`
+	PageSize       int     `json:"page_size"`
+	Total          *int64  `json:"total,omitempty"`
+	TotalPages     *int64  `json:"total_pages,omitempty"`
+	TotalEstimated bool    `json:"total_estimated,omitempty"`
+	NextCursor     *Cursor `json:"next_cursor,omitempty"`
+	PrevCursor     *Cursor `json:"prev_cursor,omitempty"`
+}
+
+// SetTotal sets total number of items and calculates number of pages
+func (p *Page[T]) SetTotal(total int64, estimated bool) {
+	totalPages := int64(0)
+	if p.PageSize > 0 {
+		totalPages = (total + int64(p.PageSize) - 1) / int64(p.PageSize)
+	}
+	p.Total = &total
+	p.TotalPages = &totalPages
+	p.TotalEstimated = estimated
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SongPatch holds fields of partial song update, nil fields are left unchanged
+type SongPatch struct {
+	Group       *string
+	Title       *string
+	ReleaseDate *time.Time
+	Text        *string
+	Link        *string
+}
+
+// IsEmpty reports whether patch changes nothing
+func (p SongPatch) IsEmpty() bool {
+	return p.Group == nil && p.Title == nil && p.ReleaseDate == nil && p.Text == nil && p.Link == nil
+}
+
+// PatchOperation is single operation of JSON Patch (RFC 6902) document
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
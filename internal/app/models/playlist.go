@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Playlist represents ordered list of songs
+// ItemsCount doesn't include songs in trash, since they are hidden from playlist
+type Playlist struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ItemsCount  int       `json:"items_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PlaylistRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SongSummary represents song embedded into other objects, without its text
+type SongSummary struct {
+	ID          int        `json:"id"`
+	Group       string     `json:"group"`
+	Title       string     `json:"song"`
+	ReleaseDate *time.Time `json:"release_date"`
+	Link        string     `json:"link"`
+}
+
+// PlaylistItem represents entry of playlist, Position is 1-based
+// The same song may be added to playlist several times, so entries have their own ID
+type PlaylistItem struct {
+	ID       int         `json:"id"`
+	Position int         `json:"position"`
+	AddedAt  time.Time   `json:"added_at"`
+	Song     SongSummary `json:"song"`
+}
+
+// PlaylistDetail represents playlist with its entries in order
+type PlaylistDetail struct {
+	Playlist
+	Items []PlaylistItem `json:"items"`
+}
+
+// PlaylistItemRequest represents song to add to playlist, Position 0 means the end of playlist
+type PlaylistItemRequest struct {
+	SongID   int `json:"song_id"`
+	Position int `json:"position"`
+}
+
+// PlaylistMoveRequest represents new position of playlist entry
+type PlaylistMoveRequest struct {
+	Position int `json:"position"`
+}
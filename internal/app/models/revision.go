@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Operations which produce revision of song
+// Every change of song in trash is recorded as delete, since song stays deleted
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// SongRevision represents snapshot of song taken after each change
+// Revision equals version of song after change
+type SongRevision struct {
+	SongID           int        `json:"song_id"`
+	Revision         int        `json:"revision"`
+	Operation        string     `json:"operation"`
+	ArtistID         int        `json:"artist_id"`
+	Group            string     `json:"group"`
+	Title            string     `json:"song"`
+	ReleaseDate      *time.Time `json:"release_date"`
+	Text             string     `json:"text"`
+	Link             string     `json:"link"`
+	EnrichmentStatus string     `json:"enrichment_status"`
+	ChangedAt        time.Time  `json:"changed_at"`
+}
+
+// Operations of lines in diff of song text
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine represents line of text diff. FromLine and ToLine are 1-based line numbers
+// in texts of revisions from and to, line missing in one of them has 0 there
+type DiffLine struct {
+	Op       string `json:"op"`
+	Text     string `json:"text"`
+	FromLine int    `json:"from_line,omitempty"`
+	ToLine   int    `json:"to_line,omitempty"`
+}
+
+// FieldChange represents change of song field other than text
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RevisionDiff represents difference between two revisions of song
+type RevisionDiff struct {
+	SongID int           `json:"song_id"`
+	From   int           `json:"from"`
+	To     int           `json:"to"`
+	Fields []FieldChange `json:"fields"`
+	Lines  []DiffLine    `json:"lines"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// Search languages
+const (
+	SearchLangRussian = "ru"
+	SearchLangEnglish = "en"
+)
+
+// SongSearch holds full-text search query
+// Query uses web search syntax: "quoted phrase", OR, -excluded word
+// Empty Lang means search in both russian and english configurations
+type SongSearch struct {
+	Query string
+	Lang  string
+}
+
+// SongSearchResult represents song found by full-text search with its rank and highlighted snippet of text
+type SongSearchResult struct {
+	Song
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
@@ -2,23 +2,52 @@ package models
 
 import "time"
 
+// Enrichment statuses of song
+const (
+	EnrichmentPending = "pending"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+)
+
 // Song represents structure of song in library
+// ReleaseDate is nil until song details are fetched from external API.
+// Version is incremented on every change of song and serves as its ETag.
+// DeletedAt is set while song is in trash
 type Song struct {
-	ID          int       `json:"id"`
-	Group       string    `json:"group"`
-	Title       string    `json:"song"`
-	ReleaseDate time.Time `json:"release_date"`
-	Text        string    `json:"text"`
-	Link        string    `json:"link"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               int        `json:"id"`
+	ArtistID         int        `json:"artist_id"`
+	Group            string     `json:"group"`
+	Title            string     `json:"song"`
+	ReleaseDate      *time.Time `json:"release_date"`
+	Text             string     `json:"text"`
+	Link             string     `json:"link"`
+	EnrichmentStatus string     `json:"enrichment_status"`
+	Version          int        `json:"version"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
 }
 
+// Match modes of group and title filters, all of them are case-insensitive
+const (
+	MatchExact    = "exact"
+	MatchPrefix   = "prefix"
+	MatchContains = "contains"
+)
+
 // SongFilters holds optional fields to filter songs
+// Song matches Groups if it matches any of them. ReleaseTo is inclusive.
+// Non-zero AsOf selects songs as they were at that moment instead of current ones
 type SongFilters struct {
-	Group       string    `json:"group"`
-	Title       string    `json:"song"`
-	ReleaseDate time.Time `json:"release_date"`
+	Groups       []string  `json:"group"`
+	Title        string    `json:"song"`
+	Match        string    `json:"match"`
+	ReleaseDate  time.Time `json:"release_date"`
+	ReleaseFrom  time.Time `json:"release_from"`
+	ReleaseTo    time.Time `json:"release_to"`
+	CreatedAfter time.Time `json:"created_after"`
+	UpdatedAfter time.Time `json:"updated_after"`
+	AsOf         time.Time `json:"as_of"`
 }
 
 type SongDetail struct {
@@ -27,6 +56,14 @@ type SongDetail struct {
 	Link        string `json:"link"`
 }
 
+// SongText represents page of song text, split into verses or lines
+// Version and UpdatedAt of song are kept to validate conditional requests
+type SongText[T any] struct {
+	Page[T]
+	Version   int       `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
 type AddSongRequest struct {
 	Group string `json:"group"`
 	Song  string `json:"song"`
@@ -0,0 +1,50 @@
+package models
+
+import "strings"
+
+// Columns songs may be sorted by
+const (
+	SortByID          = "id"
+	SortByGroup       = "group"
+	SortByTitle       = "song"
+	SortByReleaseDate = "release_date"
+	SortByCreatedAt   = "created_at"
+	SortByUpdatedAt   = "updated_at"
+)
+
+// Collations of text columns
+const (
+	CollationRussian = "ru"
+	CollationEnglish = "en"
+)
+
+// SortField is single column of sort order
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// SongSort describes order of songs, empty Fields mean default order (newest releases first)
+// Collation applies to text columns, empty one means database default
+type SongSort struct {
+	Fields    []SortField
+	Collation string
+}
+
+// String returns sort in query parameter syntax, e.g. "-release_date,group", followed by "@collation" if set
+func (s SongSort) String() string {
+	parts := make([]string, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.Desc {
+			parts = append(parts, "-"+field.Column)
+		} else {
+			parts = append(parts, field.Column)
+		}
+	}
+
+	result := strings.Join(parts, ",")
+	if s.Collation != "" {
+		result += "@" + s.Collation
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package models
+
+import "math"
+
+// MaxOffsetMs is the latest time line can be sung at, timings are stored in INT column
+const MaxOffsetMs = math.MaxInt32
+
+// SyncedLine represents line of song text with time it is sung at, in milliseconds from the start of song
+type SyncedLine struct {
+	Number   int    `json:"number"`
+	Verse    int    `json:"verse"`
+	Text     string `json:"text"`
+	OffsetMs int    `json:"offset_ms"`
+}
+
+// SyncedLyrics represents time-synchronized text of song
+// Every line of song text has its timing, Version is version of song timings were set for
+type SyncedLyrics struct {
+	SongID  int          `json:"song_id"`
+	Group   string       `json:"group"`
+	Title   string       `json:"song"`
+	Version int          `json:"version"`
+	Lines   []SyncedLine `json:"lines"`
+}
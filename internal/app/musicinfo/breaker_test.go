@@ -0,0 +1,98 @@
+package musicinfo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	const cooldown = 30 * time.Second
+
+	// Steps: allow and deny call Allow expecting true and false, wait advances clock by cooldown,
+	// wait-half by half of it, other steps record outcome of call
+	type step struct {
+		op   string
+		want State
+	}
+	open := []step{
+		{"allow", StateClosed}, {"failure", StateClosed},
+		{"allow", StateClosed}, {"failure", StateClosed},
+		{"allow", StateClosed}, {"failure", StateOpen},
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{name: "opens after threshold failures", steps: append(open, step{"deny", StateOpen})},
+		{
+			name: "success resets failures",
+			steps: []step{
+				{"failure", StateClosed}, {"failure", StateClosed}, {"success", StateClosed},
+				{"failure", StateClosed}, {"failure", StateClosed}, {"allow", StateClosed},
+			},
+		},
+		{name: "stays open during cooldown", steps: append(open, step{"wait-half", StateOpen}, step{"deny", StateOpen})},
+		{
+			name:  "half-open after cooldown lets single probe",
+			steps: append(open, step{"wait", StateHalfOpen}, step{"allow", StateHalfOpen}, step{"deny", StateHalfOpen}),
+		},
+		{
+			name: "successful probe closes",
+			steps: append(open, step{"wait", StateHalfOpen}, step{"allow", StateHalfOpen}, step{"success", StateClosed},
+				step{"allow", StateClosed}, step{"allow", StateClosed}),
+		},
+		{
+			name: "failed probe opens again",
+			steps: append(open, step{"wait", StateHalfOpen}, step{"allow", StateHalfOpen}, step{"failure", StateOpen},
+				step{"deny", StateOpen}, step{"wait", StateHalfOpen}, step{"allow", StateHalfOpen}),
+		},
+		{
+			name: "cancelled probe lets another one",
+			steps: append(open, step{"wait", StateHalfOpen}, step{"allow", StateHalfOpen}, step{"cancel", StateHalfOpen},
+				step{"allow", StateHalfOpen}, step{"deny", StateHalfOpen}),
+		},
+		{
+			name:  "cancel doesn't count as failure",
+			steps: []step{{"failure", StateClosed}, {"failure", StateClosed}, {"cancel", StateClosed}, {"allow", StateClosed}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+			b := NewBreaker(3, cooldown)
+			b.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				switch s.op {
+				case "allow", "deny":
+					if got := b.Allow(); got != (s.op == "allow") {
+						t.Fatalf("step %d: Allow() = %v", i+1, got)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "cancel":
+					b.Cancel()
+				case "wait":
+					now = now.Add(cooldown)
+				case "wait-half":
+					now = now.Add(cooldown / 2)
+				}
+				if got := b.State(); got != s.want {
+					t.Fatalf("step %d (%s): State() = %s, want %s", i+1, s.op, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerMinimalThreshold(t *testing.T) {
+	b := NewBreaker(0, time.Minute)
+	b.Failure()
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("State() = %s after single failure, want open", got)
+	}
+}
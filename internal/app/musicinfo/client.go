@@ -0,0 +1,260 @@
+package musicinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+)
+
+// Config holds settings for music info HTTP client
+type Config struct {
+	BaseURL          string
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryBackoff     time.Duration
+	MaxRetryBackoff  time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Client implements MusicInfoProvider over HTTP
+// Every attempt is bounded by timeout, failed attempts are retried with jittered
+// exponential backoff and guarded by circuit breaker
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	breaker *Breaker
+	logger  *logrus.Logger
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient creates new Client instance and takes Config and logger as parameters
+func NewClient(cfg Config, logger *logrus.Logger) *Client {
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		logger:  logger,
+		sleep:   sleepContext,
+	}
+}
+
+// BreakerState returns current state of client circuit breaker
+func (c *Client) BreakerState() State {
+	return c.breaker.State()
+}
+
+// GetSongDetail requests song details from /info endpoint of music info service
+// It returns ErrNotFound, ErrRejected, ErrUnavailable, ErrTimeout or ErrCircuitOpen wrapped into *Error on failure
+// Requests and pauses between retries are cancelled together with ctx, which is not counted as failure of service.
+// Neither is rate limit (429), which is retried after Retry-After like other failures
+func (c *Client) GetSongDetail(ctx context.Context, group, song string) (models.SongDetail, error) {
+	query := url.Values{}
+	query.Set("group", group)
+	query.Set("song", song)
+	infoURL := c.cfg.BaseURL + "/info?" + query.Encode()
+
+	c.logger.Infof("GetSongDetail[musicinfo]: Запрос деталей песни: %s", infoURL)
+
+	var lastErr *Error
+	for attempt := 1; attempt <= c.cfg.MaxRetries+1; attempt++ {
+		if !c.breaker.Allow() {
+			c.logger.Warnf("GetSongDetail[musicinfo]: Запрос отклонен, circuit breaker открыт")
+			return models.SongDetail{}, &Error{Attempts: attempt - 1, Err: ErrCircuitOpen, Cause: causeOf(lastErr)}
+		}
+
+		detail, retryAfter, err := c.do(ctx, infoURL)
+		if err == nil {
+			c.breaker.Success()
+			c.logger.Infof("GetSongDetail[musicinfo]: Успешно получены детали песни, попытка: %d", attempt)
+			return detail, nil
+		}
+		err.Attempts = attempt
+
+		// Caller gave up, so failure says nothing about health of service
+		if ctx.Err() != nil {
+			c.breaker.Cancel()
+			c.logger.Warnf("GetSongDetail[musicinfo]: Запрос отменен: %v", ctx.Err())
+			return models.SongDetail{}, err
+		}
+
+		// Not found is valid answer of healthy service, it is neither retried nor counted as failure
+		if errors.Is(err, ErrNotFound) {
+			c.breaker.Success()
+			c.logger.Warnf("GetSongDetail[musicinfo]: Песня не найдена во внешнем API")
+			return models.SongDetail{}, err
+		}
+		// Rejected request fails the same way every time, though service itself is healthy
+		if errors.Is(err, ErrRejected) {
+			c.breaker.Success()
+			c.logger.Errorf("GetSongDetail[musicinfo]: Внешний API отклонил запрос: %v", err)
+			return models.SongDetail{}, err
+		}
+
+		// Rate limited service is up and only asks to slow down, so attempt is retried after Retry-After
+		// without being counted as failure
+		if err.StatusCode == http.StatusTooManyRequests {
+			c.breaker.Cancel()
+		} else {
+			c.breaker.Failure()
+		}
+		lastErr = err
+		c.logger.Warnf("GetSongDetail[musicinfo]: Попытка %d завершилась ошибкой: %v", attempt, err)
+
+		if attempt > c.cfg.MaxRetries {
+			break
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > 0 {
+			// Upstream asked to wait longer than we are ready to, so give up right away
+			if retryAfter > c.cfg.MaxRetryBackoff {
+				c.logger.Warnf("GetSongDetail[musicinfo]: Retry-After %s превышает допустимую паузу", retryAfter)
+				break
+			}
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+		}
+		if cerr := c.sleep(ctx, delay); cerr != nil {
+			c.logger.Warnf("GetSongDetail[musicinfo]: Повтор отменен: %v", cerr)
+			lastErr.Cause = cerr
+			break
+		}
+	}
+
+	c.logger.Errorf("GetSongDetail[musicinfo]: Не удалось получить детали песни: %v", lastErr)
+	return models.SongDetail{}, lastErr
+}
+
+// Probe checks that music info service responds, with single attempt regardless of circuit breaker
+// Any response except 5xx means that service is up
+func (c *Client) Probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.BaseURL+"/info", nil)
+	if err != nil {
+		return &Error{Attempts: 1, Err: ErrUnavailable, Cause: err}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return &Error{Attempts: 1, Err: ErrTimeout, Cause: err}
+		}
+		return &Error{Attempts: 1, Err: ErrUnavailable, Cause: err}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &Error{Attempts: 1, StatusCode: resp.StatusCode, Err: ErrUnavailable}
+	}
+	return nil
+}
+
+// do performs single attempt and returns song details, Retry-After delay and error if any occurs
+func (c *Client) do(ctx context.Context, infoURL string) (models.SongDetail, time.Duration, *Error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, infoURL, nil)
+	if err != nil {
+		return models.SongDetail{}, 0, &Error{Err: ErrUnavailable, Cause: err}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return models.SongDetail{}, 0, &Error{Err: ErrTimeout, Cause: err}
+		}
+		return models.SongDetail{}, 0, &Error{Err: ErrUnavailable, Cause: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return models.SongDetail{}, 0, &Error{StatusCode: resp.StatusCode, Err: ErrNotFound}
+	case resp.StatusCode == http.StatusGatewayTimeout || resp.StatusCode == http.StatusRequestTimeout:
+		return models.SongDetail{}, parseRetryAfter(resp.Header.Get("Retry-After")),
+			&Error{StatusCode: resp.StatusCode, Err: ErrTimeout}
+	case resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError &&
+		resp.StatusCode != http.StatusTooManyRequests:
+		// Client errors other than timeout and rate limit won't go away on retry
+		return models.SongDetail{}, 0, &Error{StatusCode: resp.StatusCode, Err: ErrRejected}
+	default:
+		return models.SongDetail{}, parseRetryAfter(resp.Header.Get("Retry-After")),
+			&Error{StatusCode: resp.StatusCode, Err: ErrUnavailable}
+	}
+
+	var detail models.SongDetail
+	if err = json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		if isTimeout(err) {
+			return models.SongDetail{}, 0, &Error{StatusCode: resp.StatusCode, Err: ErrTimeout, Cause: err}
+		}
+		return models.SongDetail{}, 0, &Error{StatusCode: resp.StatusCode, Err: ErrUnavailable, Cause: err}
+	}
+	return detail, 0, nil
+}
+
+// backoff returns random delay in [0, min(MaxRetryBackoff, RetryBackoff*2^(attempt-1))] (full jitter)
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.RetryBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.cfg.MaxRetryBackoff {
+		ceiling = c.cfg.MaxRetryBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// parseRetryAfter parses Retry-After header given either in seconds or as HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleepContext pauses for d, returning earlier with error of ctx if it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isTimeout reports whether error is caused by exceeded deadline
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// causeOf returns last upstream error to be attached to circuit open error
+func causeOf(err *Error) error {
+	if err == nil {
+		return nil
+	}
+	return err
+}
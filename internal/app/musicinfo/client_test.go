@@ -0,0 +1,253 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+)
+
+// response is scripted response of test music info service
+type response struct {
+	status     int
+	retryAfter string
+}
+
+const detailJSON = `{"releaseDate":"16.07.2006","text":"Ooh baby, don't you know I suffer?","link":"https://www.youtube.com/watch?v=Xsp3_a-PMTw"}`
+
+// newTestService starts music info service answering with responses in order, repeating the last one
+// It returns service and function reporting number of requests it received
+func newTestService(t *testing.T, responses []response) (*httptest.Server, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		resp := responses[min(requests, len(responses)-1)]
+		requests++
+		mu.Unlock()
+
+		if r.URL.Path != "/info" || r.URL.Query().Get("group") != "Muse" || r.URL.Query().Get("song") != "Supermassive Black Hole" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusTeapot)
+			return
+		}
+		if resp.retryAfter != "" {
+			w.Header().Set("Retry-After", resp.retryAfter)
+		}
+		w.WriteHeader(resp.status)
+		if resp.status == http.StatusOK {
+			io.WriteString(w, detailJSON)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestGetSongDetail(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []response
+		threshold    int
+		wantErr      error
+		wantStatus   int
+		wantAttempts int
+		wantRequests int
+		wantPauses   []time.Duration
+		wantState    State
+	}{
+		{name: "success", responses: []response{{status: http.StatusOK}}, wantRequests: 1},
+		{
+			name:      "not found",
+			responses: []response{{status: http.StatusNotFound}},
+			wantErr:   ErrNotFound, wantStatus: http.StatusNotFound, wantAttempts: 1, wantRequests: 1,
+		},
+		{
+			name:      "rejected",
+			responses: []response{{status: http.StatusBadRequest}},
+			wantErr:   ErrRejected, wantStatus: http.StatusBadRequest, wantAttempts: 1, wantRequests: 1,
+		},
+		{
+			name:         "retried server errors",
+			responses:    []response{{status: http.StatusInternalServerError}, {status: http.StatusBadGateway}, {status: http.StatusOK}},
+			wantRequests: 3, wantPauses: []time.Duration{0, 0},
+		},
+		{
+			name:      "retries exhausted",
+			responses: []response{{status: http.StatusServiceUnavailable}},
+			wantErr:   ErrUnavailable, wantStatus: http.StatusServiceUnavailable, wantAttempts: 3, wantRequests: 3,
+			wantPauses: []time.Duration{0, 0},
+		},
+		{
+			name:      "request timeout",
+			responses: []response{{status: http.StatusRequestTimeout}},
+			wantErr:   ErrTimeout, wantStatus: http.StatusRequestTimeout, wantAttempts: 3, wantRequests: 3,
+			wantPauses: []time.Duration{0, 0},
+		},
+		{
+			name:         "Retry-After in seconds",
+			responses:    []response{{status: http.StatusServiceUnavailable, retryAfter: "1"}, {status: http.StatusOK}},
+			wantRequests: 2, wantPauses: []time.Duration{time.Second},
+		},
+		{
+			name:      "Retry-After beyond maximal pause",
+			responses: []response{{status: http.StatusServiceUnavailable, retryAfter: "3"}},
+			wantErr:   ErrUnavailable, wantStatus: http.StatusServiceUnavailable, wantAttempts: 1, wantRequests: 1,
+		},
+		{
+			name:         "malformed Retry-After",
+			responses:    []response{{status: http.StatusServiceUnavailable, retryAfter: "soon"}, {status: http.StatusOK}},
+			wantRequests: 2, wantPauses: []time.Duration{0},
+		},
+		{
+			name:         "rate limit is retried after Retry-After",
+			responses:    []response{{status: http.StatusTooManyRequests, retryAfter: "1"}, {status: http.StatusOK}},
+			wantRequests: 2, wantPauses: []time.Duration{time.Second},
+		},
+		{
+			name:      "rate limit doesn't open breaker",
+			responses: []response{{status: http.StatusTooManyRequests, retryAfter: "1"}},
+			threshold: 2,
+			wantErr:   ErrUnavailable, wantStatus: http.StatusTooManyRequests, wantAttempts: 3, wantRequests: 3,
+			wantPauses: []time.Duration{time.Second, time.Second}, wantState: StateClosed,
+		},
+		{
+			name:      "failures open breaker",
+			responses: []response{{status: http.StatusServiceUnavailable}},
+			threshold: 2,
+			wantErr:   ErrCircuitOpen, wantAttempts: 2, wantRequests: 2,
+			wantPauses: []time.Duration{0, 0}, wantState: StateOpen,
+		},
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newTestService(t, tt.responses)
+			threshold := tt.threshold
+			if threshold == 0 {
+				threshold = 10
+			}
+			client := NewClient(Config{
+				BaseURL:          server.URL,
+				Timeout:          time.Second,
+				MaxRetries:       2,
+				RetryBackoff:     time.Nanosecond,
+				MaxRetryBackoff:  2 * time.Second,
+				BreakerThreshold: threshold,
+				BreakerCooldown:  time.Minute,
+			}, logger)
+
+			// Backoff is below a microsecond, so only pauses of Retry-After remain after truncation
+			var pauses []time.Duration
+			client.sleep = func(_ context.Context, d time.Duration) error {
+				pauses = append(pauses, d.Truncate(time.Microsecond))
+				return nil
+			}
+
+			detail, err := client.GetSongDetail(context.Background(), "Muse", "Supermassive Black Hole")
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("GetSongDetail() error = %v", err)
+				}
+				want := models.SongDetail{
+					ReleaseDate: "16.07.2006",
+					Text:        "Ooh baby, don't you know I suffer?",
+					Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+				}
+				if detail != want {
+					t.Fatalf("GetSongDetail() = %+v, want %+v", detail, want)
+				}
+			} else {
+				var clientErr *Error
+				if !errors.As(err, &clientErr) || !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetSongDetail() error = %v, want %v", err, tt.wantErr)
+				}
+				if clientErr.StatusCode != tt.wantStatus || clientErr.Attempts != tt.wantAttempts {
+					t.Fatalf("GetSongDetail() error has status %d and %d attempts, want %d and %d",
+						clientErr.StatusCode, clientErr.Attempts, tt.wantStatus, tt.wantAttempts)
+				}
+			}
+
+			if got := requests(); got != tt.wantRequests {
+				t.Fatalf("service received %d requests, want %d", got, tt.wantRequests)
+			}
+			if len(pauses) != len(tt.wantPauses) {
+				t.Fatalf("pauses = %v, want %v", pauses, tt.wantPauses)
+			}
+			for i := range pauses {
+				if pauses[i] != tt.wantPauses[i] {
+					t.Fatalf("pauses = %v, want %v", pauses, tt.wantPauses)
+				}
+			}
+			if got := client.BreakerState(); got != tt.wantState {
+				t.Fatalf("BreakerState() = %s, want %s", got, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestGetSongDetailCancelled(t *testing.T) {
+	server, requests := newTestService(t, []response{{status: http.StatusServiceUnavailable, retryAfter: "1"}})
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	client := NewClient(Config{BaseURL: server.URL, Timeout: time.Second, MaxRetries: 2, MaxRetryBackoff: 2 * time.Second, BreakerThreshold: 1}, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleepContext(ctx, d)
+	}
+
+	_, err := client.GetSongDetail(ctx, "Muse", "Supermassive Black Hole")
+	var clientErr *Error
+	if !errors.As(err, &clientErr) || !errors.Is(err, ErrUnavailable) || !errors.Is(clientErr.Cause, context.Canceled) {
+		t.Fatalf("GetSongDetail() error = %v, want ErrUnavailable caused by cancellation", err)
+	}
+	if got := requests(); got != 1 {
+		t.Fatalf("service received %d requests, want 1", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "zero", value: "0", want: 0},
+		{name: "negative", value: "-5", want: 0},
+		{name: "malformed", value: "soon", want: 0},
+		{name: "past date", value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got != tt.want {
+				t.Fatalf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	// Date is converted to time left until it, which is slightly less than when date was formatted
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 58*time.Minute || got > time.Hour {
+		t.Fatalf("parseRetryAfter(%q) = %s, want about an hour", date, got)
+	}
+}
@@ -0,0 +1,47 @@
+package musicinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"rest-songs/internal/app/models"
+)
+
+var (
+	ErrNotFound    = errors.New("song info not found")
+	ErrRejected    = errors.New("music info service rejected request")
+	ErrUnavailable = errors.New("music info service unavailable")
+	ErrTimeout     = errors.New("music info service timeout")
+	ErrCircuitOpen = errors.New("music info circuit breaker is open")
+)
+
+// MusicInfoProvider defines interface for fetching song details from external music info service
+type MusicInfoProvider interface {
+	GetSongDetail(ctx context.Context, group, song string) (models.SongDetail, error)
+}
+
+// Error describes failed request to music info service
+// It wraps one of the sentinel errors above, so callers can use errors.Is
+type Error struct {
+	StatusCode int
+	Attempts   int
+	Err        error
+	Cause      error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%v (attempts: %d", e.Err, e.Attempts)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(", status: %d", e.StatusCode)
+	}
+	msg += ")"
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
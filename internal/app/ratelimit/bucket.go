@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// bucket is token bucket refilled at constant rate up to its capacity
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills bucket for time passed and takes one token if there is one
+// It returns whether token was taken, tokens left, time until the next token and time until bucket is full
+func (b *bucket) take(now time.Time, limit Limit) (bool, int, time.Duration, time.Duration) {
+	rate := limit.rate()
+	capacity := float64(limit.burst())
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	var retryAfter time.Duration
+	if b.tokens < 1 {
+		retryAfter = seconds((1 - b.tokens) / rate)
+	}
+	reset := seconds((capacity - b.tokens) / rate)
+	return allowed, int(b.tokens), retryAfter, reset
+}
+
+// full reports whether bucket would be full at given time, such buckets may be forgotten
+func (b *bucket) full(now time.Time, limit Limit) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.rate() >= float64(limit.burst())
+}
+
+// seconds converts fractional number of seconds to duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,307 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/auth"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// Classes of requests, each class has its own limits
+// Upstream requests make calls to external metadata API, so they are limited the most.
+// IP class limits all requests from address before authentication, so invalid credentials are limited too
+const (
+	ClassRead     = "read"
+	ClassWrite    = "write"
+	ClassUpstream = "upstream"
+	ClassIP       = "ip"
+)
+
+// Intervals of background maintenance
+const (
+	sweepInterval      = time.Minute
+	quotaPurgeInterval = 24 * time.Hour
+	quotaRetentionDays = 7
+	secondsPerDay      = 24 * 60 * 60
+)
+
+// Limit holds limits of request class
+// Requests are limited by token bucket refilled at PerMinute tokens per minute and holding up to Burst tokens,
+// and by Daily quota, which is persisted in database. Zero PerMinute or Daily disables that limit
+type Limit struct {
+	PerMinute int
+	Burst     int
+	Daily     int
+}
+
+// rate returns number of tokens added to bucket per second
+func (l Limit) rate() float64 {
+	return float64(l.PerMinute) / 60
+}
+
+// burst returns capacity of bucket, which is at least one token
+func (l Limit) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+// Config holds configuration of rate limiting
+// IP limit applies to every request from address regardless of credentials, its Daily quota is not used.
+// Requests to Exempt path prefixes are not limited
+type Config struct {
+	Enabled  bool
+	Read     Limit
+	Write    Limit
+	Upstream Limit
+	IP       Limit
+	Exempt   []string
+}
+
+// status describes state of limit after request, it is reported in RateLimit-* headers
+type status struct {
+	limit     int
+	remaining int
+	reset     time.Duration
+}
+
+// Limiter limits requests of every client, identified by API key, token subject or IP address
+type Limiter struct {
+	quotas    postgresql.QuotaRepository
+	cfg       Config
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+	logger    *logrus.Logger
+}
+
+// New creates new Limiter instance and takes quota repository, Config and logger as parameters
+func New(quotas postgresql.QuotaRepository, cfg Config, logger *logrus.Logger) *Limiter {
+	return &Limiter{
+		quotas:    quotas,
+		cfg:       cfg,
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// Classify returns class of request
+// POST /songs enqueues enrichment of song from external API, so it is upstream request
+func Classify(r *http.Request) string {
+	switch {
+	case r.Method == http.MethodPost && strings.TrimSuffix(r.URL.Path, "/") == "/songs":
+		return ClassUpstream
+	case r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions:
+		return ClassRead
+	default:
+		return ClassWrite
+	}
+}
+
+// Middleware limits requests and reports limits in RateLimit-Policy, RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers. Limited requests get 429 with Retry-After.
+// Authentication middleware must run before, so that clients are identified by their keys
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.cfg.Enabled || l.exempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		class := Classify(r)
+		limit := l.limit(class)
+		client := clientKey(r)
+		now := l.now()
+
+		var policies []string
+		var reported *status
+
+		if limit.PerMinute > 0 {
+			allowed, remaining, retryAfter, reset := l.take(client+"|"+class, now, limit)
+			refill := int(math.Ceil(float64(limit.burst()) / limit.rate()))
+			policies = append(policies, fmt.Sprintf("%d;w=%d", limit.burst(), refill))
+			reported = &status{limit: limit.burst(), remaining: remaining, reset: reset}
+
+			if !allowed {
+				l.logger.Warnf("Middleware[ratelimit]: Превышен лимит %s клиента %s", class, client)
+				writeTooManyRequests(w, policies, *reported, retryAfter)
+				return
+			}
+		}
+
+		if limit.Daily > 0 {
+			day := now.UTC().Truncate(24 * time.Hour)
+			untilTomorrow := day.Add(24 * time.Hour).Sub(now)
+			policies = append(policies, fmt.Sprintf("%d;w=%d", limit.Daily, secondsPerDay))
+
+			used, allowed, err := l.quotas.ConsumeQuota(r.Context(), client, class, day, limit.Daily)
+			if err != nil {
+				// Quota store being unavailable shouldn't take API down, token bucket still limits requests
+				l.logger.Errorf("Middleware[ratelimit]: Ошибка учета квоты клиента %s: %v", client, err)
+			} else {
+				quota := status{limit: limit.Daily, remaining: limit.Daily - int(used), reset: untilTomorrow}
+				if reported == nil || quota.remaining < reported.remaining {
+					reported = &quota
+				}
+				if !allowed {
+					l.logger.Warnf("Middleware[ratelimit]: Исчерпана дневная квота %s клиента %s", class, client)
+					writeTooManyRequests(w, policies, quota, untilTomorrow)
+					return
+				}
+			}
+		}
+
+		if reported != nil {
+			setHeaders(w, policies, *reported)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IPMiddleware limits requests by IP address of client and must run before authentication,
+// so that guessing of API keys and tokens is limited as well. Limited requests get 429 with Retry-After
+func (l *Limiter) IPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.cfg.Enabled || l.cfg.IP.PerMinute <= 0 || l.exempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := l.cfg.IP
+		client := ipKey(r)
+		allowed, remaining, retryAfter, reset := l.take(client+"|"+ClassIP, l.now(), limit)
+		if !allowed {
+			refill := int(math.Ceil(float64(limit.burst()) / limit.rate()))
+			l.logger.Warnf("IPMiddleware[ratelimit]: Превышен лимит запросов с адреса %s", client)
+			writeTooManyRequests(w, []string{fmt.Sprintf("%d;w=%d", limit.burst(), refill)},
+				status{limit: limit.burst(), remaining: remaining, reset: reset}, retryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Run removes quota counters of past days at start and then once a day, blocking until ctx is cancelled
+func (l *Limiter) Run(ctx context.Context) {
+	if !l.cfg.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(quotaPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		before := l.now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -quotaRetentionDays)
+		if removed, err := l.quotas.PurgeQuotas(ctx, before); err != nil {
+			l.logger.Errorf("Run[ratelimit]: Ошибка удаления старых квот: %v", err)
+		} else if removed > 0 {
+			l.logger.Infof("Run[ratelimit]: Удалено %d старых счетчиков квот", removed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// take takes token from bucket of client and class, creating full bucket for new clients
+// Full buckets of idle clients are forgotten from time to time, so memory doesn't grow with number of clients
+func (l *Limiter) take(key string, now time.Time, limit Limit) (bool, int, time.Duration, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for k, b := range l.buckets {
+			if b.full(now, l.limit(k[strings.LastIndexByte(k, '|')+1:])) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.burst()), updated: now}
+		l.buckets[key] = b
+	}
+	return b.take(now, limit)
+}
+
+// limit returns limits of request class
+func (l *Limiter) limit(class string) Limit {
+	switch class {
+	case ClassUpstream:
+		return l.cfg.Upstream
+	case ClassWrite:
+		return l.cfg.Write
+	case ClassIP:
+		return l.cfg.IP
+	default:
+		return l.cfg.Read
+	}
+}
+
+// exempt reports whether path is not limited
+func (l *Limiter) exempt(path string) bool {
+	for _, prefix := range l.cfg.Exempt {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientKey identifies client by API key or token subject, anonymous clients are identified by IP address
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		if principal.KeyID != 0 {
+			return "key:" + strconv.Itoa(principal.KeyID)
+		}
+		return principal.Method + ":" + principal.Subject
+	}
+	return ipKey(r)
+}
+
+// ipKey identifies client by IP address
+func ipKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// setHeaders sets RateLimit-* headers, reset is rounded up to whole seconds
+func setHeaders(w http.ResponseWriter, policies []string, s status) {
+	w.Header().Set("RateLimit-Policy", strings.Join(policies, ", "))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(s.limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(s.remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(s.reset)))
+}
+
+// writeTooManyRequests responds with 429 and Retry-After header
+func writeTooManyRequests(w http.ResponseWriter, policies []string, s status, retryAfter time.Duration) {
+	setHeaders(w, policies, s)
+	w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+	http.Error(w, "Слишком много запросов", http.StatusTooManyRequests)
+}
+
+// ceilSeconds rounds duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,133 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// apiKeyColumns lists columns of api_keys table in order expected by scanAPIKey
+const apiKeyColumns = `id, name, prefix, role, created_at, last_used_at, revoked_at`
+
+// APIKeyRepository defines methods for API keys, keys are stored as SHA-256 hashes
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, name, prefix, role string, hash []byte) (models.APIKey, error)
+	GetAPIKeys(ctx context.Context, page, pageSize int) ([]models.APIKey, error)
+	CountAPIKeys(ctx context.Context) (int64, error)
+	GetAPIKeyByHash(ctx context.Context, hash []byte) (models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+}
+
+// CreateAPIKey stores hash of new API key
+func (r *Repo) CreateAPIKey(ctx context.Context, name, prefix, role string, hash []byte) (models.APIKey, error) {
+	r.logger.Infof("CreateAPIKey[repo]: Создание ключа %s с ролью %s", name, role)
+
+	query := `INSERT INTO api_keys (name, prefix, key_hash, role, created_at) VALUES ($1, $2, $3, $4, NOW())
+              RETURNING ` + apiKeyColumns
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var key models.APIKey
+	if err := scanAPIKey(r.db.GetPool().QueryRow(ctx, query, name, prefix, hash, role), &key); err != nil {
+		r.logger.Errorf("CreateAPIKey[repo]: Ошибка создания ключа %s: %v", name, err)
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+// GetAPIKeys retrieves API keys, newest first, supporting pagination. Revoked keys are included
+func (r *Repo) GetAPIKeys(ctx context.Context, page, pageSize int) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id DESC LIMIT $1 OFFSET $2`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		r.logger.Errorf("GetAPIKeys[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+		if err = scanAPIKey(rows, &key); err != nil {
+			r.logger.Errorf("GetAPIKeys[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetAPIKeys[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+	return keys, nil
+}
+
+// CountAPIKeys returns number of API keys, including revoked ones
+func (r *Repo) CountAPIKeys(ctx context.Context) (int64, error) {
+	query := `SELECT COUNT(*) FROM api_keys`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var total int64
+	if err := r.db.GetPool().QueryRow(ctx, query).Scan(&total); err != nil {
+		r.logger.Errorf("CountAPIKeys[repo]: Ошибка подсчета ключей: %v", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetAPIKeyByHash retrieves active API key by hash and records its use
+// Time of last use is written at most once a minute, so that every request doesn't update the row
+func (r *Repo) GetAPIKeyByHash(ctx context.Context, hash []byte) (models.APIKey, error) {
+	query := `WITH key AS (
+                  SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL
+              ), touched AS (
+                  UPDATE api_keys SET last_used_at = NOW() FROM key
+                  WHERE api_keys.id = key.id AND (key.last_used_at IS NULL OR key.last_used_at < NOW() - INTERVAL '1 minute')
+              )
+              SELECT ` + apiKeyColumns + ` FROM key`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var key models.APIKey
+	if err := scanAPIKey(r.db.GetPool().QueryRow(ctx, query, hash), &key); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.APIKey{}, ErrAPIKeyNotFound
+		}
+		r.logger.Errorf("GetAPIKeyByHash[repo]: Ошибка получения ключа: %v", err)
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+// RevokeAPIKey revokes API key by ID, revoked key is kept for audit
+func (r *Repo) RevokeAPIKey(ctx context.Context, id int) error {
+	r.logger.Infof("RevokeAPIKey[repo]: Отзыв ключа ID: %d", id)
+
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("RevokeAPIKey[repo]: Ошибка отзыва ключа ID %d: %v", id, err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		r.logger.Warnf("RevokeAPIKey[repo]: Активный ключ с ID %d не найден", id)
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// scanAPIKey scans row selected with apiKeyColumns into key
+func scanAPIKey(row pgx.Row, key *models.APIKey) error {
+	return row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Role, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+}
@@ -0,0 +1,243 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+var (
+	ErrArtistNotFound        = errors.New("artist not found")
+	ErrArtistExists          = errors.New("artist already exists")
+	ErrArtistHasSongs        = errors.New("artist has songs")
+	ErrArtistHasDeletedSongs = errors.New("artist has songs in trash")
+)
+
+// Postgresql error codes
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// artistColumns lists columns of artists table in order expected by scanArtist
+const artistColumns = `id, name, (SELECT COUNT(*) FROM songs WHERE songs.artist_id = artists.id AND songs.deleted_at IS NULL), created_at, updated_at`
+
+// ArtistRepository interface defines methods for interacting with artists in database
+type ArtistRepository interface {
+	GetArtists(ctx context.Context, page, pageSize int) ([]models.Artist, error)
+	GetArtistById(ctx context.Context, id int) (models.Artist, error)
+	CreateArtist(ctx context.Context, name string) (models.Artist, error)
+	UpdateArtist(ctx context.Context, id int, name string) (models.Artist, error)
+	DeleteArtist(ctx context.Context, id int) error
+	GetArtistSongs(ctx context.Context, id int) ([]models.Song, error)
+}
+
+// GetArtists retrieves artists ordered by name, supporting pagination
+func (r *Repo) GetArtists(ctx context.Context, page, pageSize int) ([]models.Artist, error) {
+	r.logger.Infof("GetArtists[repo]: Получение групп, страница: %d, размер страницы: %d", page, pageSize)
+
+	query := `SELECT ` + artistColumns + ` FROM artists ORDER BY lower(name), id LIMIT $1 OFFSET $2`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		r.logger.Errorf("GetArtists[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	for rows.Next() {
+		var artist models.Artist
+		if err = scanArtist(rows, &artist); err != nil {
+			r.logger.Errorf("GetArtists[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetArtists[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+
+	r.logger.Infof("GetArtists[repo]: Успешно получено %d групп", len(artists))
+	return artists, nil
+}
+
+// GetArtistById retrieves artist by ID from database. If artist not found, returns ErrArtistNotFound
+func (r *Repo) GetArtistById(ctx context.Context, id int) (models.Artist, error) {
+	r.logger.Infof("GetArtistById[repo]: Получение группы по ID: %d", id)
+
+	query := `SELECT ` + artistColumns + ` FROM artists WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var artist models.Artist
+	if err := scanArtist(r.db.GetPool().QueryRow(ctx, query, id), &artist); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("GetArtistById[repo]: Группа с ID %d не найдена", id)
+			return models.Artist{}, ErrArtistNotFound
+		}
+		r.logger.Errorf("GetArtistById[repo]: Ошибка получения группы по ID %d: %v", id, err)
+		return models.Artist{}, err
+	}
+
+	return artist, nil
+}
+
+// CreateArtist inserts new artist into database
+// If artist with the same name (case-insensitive) exists, returns ErrArtistExists
+func (r *Repo) CreateArtist(ctx context.Context, name string) (models.Artist, error) {
+	r.logger.Infof("CreateArtist[repo]: Создание группы: %s", name)
+
+	query := `INSERT INTO artists (name, created_at, updated_at) VALUES ($1, NOW(), NOW())
+              RETURNING ` + artistColumns
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var artist models.Artist
+	if err := scanArtist(r.db.GetPool().QueryRow(ctx, query, name), &artist); err != nil {
+		if isPgError(err, uniqueViolation) {
+			r.logger.Warnf("CreateArtist[repo]: Группа %s уже существует", name)
+			return models.Artist{}, ErrArtistExists
+		}
+		r.logger.Errorf("CreateArtist[repo]: Ошибка создания группы %s: %v", name, err)
+		return models.Artist{}, err
+	}
+
+	r.logger.Infof("CreateArtist[repo]: Успешно создана группа: %+v", artist)
+	return artist, nil
+}
+
+// UpdateArtist renames artist and all its songs in single transaction
+// Songs in trash are left as is, they take current artist name when restored
+// If artist not found, returns ErrArtistNotFound; if name is taken by another artist, returns ErrArtistExists
+func (r *Repo) UpdateArtist(ctx context.Context, id int, name string) (models.Artist, error) {
+	r.logger.Infof("UpdateArtist[repo]: Переименование группы ID: %d в %s", id, name)
+
+	query := `WITH renamed AS (
+                  UPDATE songs SET "group" = $2, updated_at = NOW() WHERE artist_id = $1 AND "group" <> $2 AND deleted_at IS NULL
+              )
+              UPDATE artists SET name = $2, updated_at = NOW() WHERE id = $1
+              RETURNING ` + artistColumns
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var artist models.Artist
+	if err := scanArtist(r.db.GetPool().QueryRow(ctx, query, id, name), &artist); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("UpdateArtist[repo]: Группа с ID %d не найдена", id)
+			return models.Artist{}, ErrArtistNotFound
+		}
+		if isPgError(err, uniqueViolation) {
+			r.logger.Warnf("UpdateArtist[repo]: Группа %s уже существует", name)
+			return models.Artist{}, ErrArtistExists
+		}
+		r.logger.Errorf("UpdateArtist[repo]: Ошибка обновления группы ID %d: %v", id, err)
+		return models.Artist{}, err
+	}
+
+	r.logger.Infof("UpdateArtist[repo]: Успешно обновлена группа: %+v", artist)
+	return artist, nil
+}
+
+// DeleteArtist removes artist from database by ID
+// Artist that still has songs can't be removed, in that case ErrArtistHasSongs is returned,
+// or ErrArtistHasDeletedSongs if all of them are in trash and wait to be purged
+func (r *Repo) DeleteArtist(ctx context.Context, id int) error {
+	r.logger.Infof("DeleteArtist[repo]: Удаление группы по ID: %d", id)
+
+	query := `DELETE FROM artists WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, id)
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			r.logger.Warnf("DeleteArtist[repo]: У группы с ID %d есть песни", id)
+			return r.artistSongsError(ctx, id)
+		}
+		r.logger.Errorf("DeleteArtist[repo]: Ошибка удаления группы по ID %d: %v", id, err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		r.logger.Warnf("DeleteArtist[repo]: Группа с ID %d не найдена для удаления", id)
+		return ErrArtistNotFound
+	}
+
+	r.logger.Infof("DeleteArtist[repo]: Успешно удалена группа по ID: %d", id)
+	return nil
+}
+
+// artistSongsError tells whether artist which can't be removed has songs outside of trash
+func (r *Repo) artistSongsError(ctx context.Context, id int) error {
+	query := `SELECT EXISTS (SELECT 1 FROM songs WHERE artist_id = $1 AND deleted_at IS NULL)`
+
+	var live bool
+	if err := r.db.GetPool().QueryRow(ctx, query, id).Scan(&live); err != nil {
+		r.logger.Errorf("DeleteArtist[repo]: Ошибка проверки песен группы ID %d: %v", id, err)
+		return err
+	}
+	if !live {
+		r.logger.Warnf("DeleteArtist[repo]: Все песни группы с ID %d находятся в корзине", id)
+		return ErrArtistHasDeletedSongs
+	}
+	return ErrArtistHasSongs
+}
+
+// GetArtistSongs retrieves discography of artist ordered by release date, oldest first
+// Songs which details are not fetched yet go last. If artist not found, returns ErrArtistNotFound
+func (r *Repo) GetArtistSongs(ctx context.Context, id int) ([]models.Song, error) {
+	r.logger.Infof("GetArtistSongs[repo]: Получение песен группы ID: %d", id)
+
+	if _, err := r.GetArtistById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + songColumns + ` FROM songs WHERE artist_id = $1 AND deleted_at IS NULL
+              ORDER BY release_date ASC NULLS LAST, id`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("GetArtistSongs[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := []models.Song{}
+	for rows.Next() {
+		var song models.Song
+		if err = scanSong(rows, &song); err != nil {
+			r.logger.Errorf("GetArtistSongs[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetArtistSongs[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+
+	r.logger.Infof("GetArtistSongs[repo]: Успешно получено %d песен", len(songs))
+	return songs, nil
+}
+
+// scanArtist scans row selected with artistColumns into artist
+func scanArtist(row pgx.Row, artist *models.Artist) error {
+	return row.Scan(&artist.ID, &artist.Name, &artist.SongsCount, &artist.CreatedAt, &artist.UpdatedAt)
+}
+
+// isPgError reports whether err is postgresql error with given code
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
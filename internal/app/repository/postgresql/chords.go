@@ -0,0 +1,83 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrChordSheetNotFound = errors.New("chord sheet not found")
+
+// ChordSheetRepository defines methods for ChordPro documents of songs
+type ChordSheetRepository interface {
+	GetChordSheet(ctx context.Context, songID int) (string, error)
+	SaveChordSheet(ctx context.Context, songID int, document string) error
+	DeleteChordSheet(ctx context.Context, songID int) error
+}
+
+// GetChordSheet retrieves ChordPro document of song
+// ErrSongNotFound is returned if song doesn't exist, ErrChordSheetNotFound if song has no document
+func (r *Repo) GetChordSheet(ctx context.Context, songID int) (string, error) {
+	query := `SELECT c.document FROM songs s
+              LEFT JOIN song_chord_sheets c ON c.song_id = s.id
+              WHERE s.id = $1 AND s.deleted_at IS NULL`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var document *string
+	if err := r.db.GetPool().QueryRow(ctx, query, songID).Scan(&document); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("GetChordSheet[repo]: Песня с ID %d не найдена", songID)
+			return "", ErrSongNotFound
+		}
+		r.logger.Errorf("GetChordSheet[repo]: Ошибка получения аккордов песни ID %d: %v", songID, err)
+		return "", err
+	}
+	if document == nil {
+		return "", ErrChordSheetNotFound
+	}
+	return *document, nil
+}
+
+// SaveChordSheet sets ChordPro document of song, replacing previous one
+func (r *Repo) SaveChordSheet(ctx context.Context, songID int, document string) error {
+	r.logger.Infof("SaveChordSheet[repo]: Сохранение аккордов песни ID: %d", songID)
+
+	query := `INSERT INTO song_chord_sheets (song_id, document)
+              SELECT id, $2 FROM songs WHERE id = $1 AND deleted_at IS NULL
+              ON CONFLICT (song_id) DO UPDATE SET document = EXCLUDED.document, updated_at = NOW()`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, songID, document)
+	if err != nil {
+		r.logger.Errorf("SaveChordSheet[repo]: Ошибка сохранения аккордов песни ID %d: %v", songID, err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		r.logger.Warnf("SaveChordSheet[repo]: Песня с ID %d не найдена", songID)
+		return ErrSongNotFound
+	}
+	return nil
+}
+
+// DeleteChordSheet removes ChordPro document of song
+func (r *Repo) DeleteChordSheet(ctx context.Context, songID int) error {
+	r.logger.Infof("DeleteChordSheet[repo]: Удаление аккордов песни ID: %d", songID)
+
+	query := `DELETE FROM song_chord_sheets c USING songs s
+              WHERE c.song_id = $1 AND s.id = c.song_id AND s.deleted_at IS NULL`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, songID)
+	if err != nil {
+		r.logger.Errorf("DeleteChordSheet[repo]: Ошибка удаления аккордов песни ID %d: %v", songID, err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrChordSheetNotFound
+	}
+	return nil
+}
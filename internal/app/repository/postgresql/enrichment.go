@@ -0,0 +1,166 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+var ErrNoJobs = errors.New("no enrichment jobs available")
+
+// EnrichmentRepository interface defines methods for persistent queue of song enrichment jobs
+type EnrichmentRepository interface {
+	CreatePending(ctx context.Context, song models.Song) (models.Song, error)
+	ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (models.EnrichmentJob, error)
+	CompleteEnrichmentJob(ctx context.Context, job models.EnrichmentJob, releaseDate time.Time, text, link string) error
+	FailEnrichmentJob(ctx context.Context, job models.EnrichmentJob, errMsg string, retryAt time.Time, dead bool) error
+	GetEnrichment(ctx context.Context, songID int) (models.SongEnrichment, error)
+}
+
+// awaitsRestore is condition of job update, true when song given in $5 waits for details in trash
+// Such job can't be finished, since song is not updated while it is in trash
+const awaitsRestore = `EXISTS (SELECT 1 FROM songs WHERE id = $5 AND deleted_at IS NOT NULL AND enrichment_status = 'pending')`
+
+// CreatePending inserts new song with pending enrichment status together with its enrichment job
+// Artist, song and job are inserted by single statement, so song is never left without job,
+// and failed insert of song doesn't leave artist without songs
+func (r *Repo) CreatePending(ctx context.Context, song models.Song) (models.Song, error) {
+	r.logger.Infof("CreatePending[repo]: Создание песни в ожидании деталей: %+v", song)
+
+	query := `WITH ` + upsertArtist + `, inserted AS (
+                  INSERT INTO songs (artist_id, "group", song, enrichment_status, created_at, updated_at)
+                  SELECT artist.id, artist.name, $2, 'pending', NOW(), NOW() FROM artist
+                  RETURNING ` + songColumns + `
+              ), job AS (
+                  INSERT INTO enrichment_jobs (song_id) SELECT id FROM inserted
+              )
+              SELECT ` + songColumns + ` FROM inserted`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	err := scanSong(r.db.GetPool().QueryRow(ctx, query, song.Group, song.Title), &song)
+	if err != nil {
+		r.logger.Errorf("CreatePending[repo]: Ошибка создания песни: %+v, ошибка: %v", song, err)
+		return models.Song{}, err
+	}
+
+	r.logger.Infof("CreatePending[repo]: Успешно создана песня: %+v", song)
+	return song, nil
+}
+
+// ClaimEnrichmentJob locks next due job and marks it as running, incrementing its attempts
+// Jobs stuck in running state longer than lease (e.g. after worker crash) are claimed again
+// Concurrent workers skip rows locked by each other. Jobs of songs in trash wait until songs are restored.
+// If there is no job, returns ErrNoJobs
+func (r *Repo) ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (models.EnrichmentJob, error) {
+	query := `UPDATE enrichment_jobs j
+              SET status = 'running', attempts = j.attempts + 1, locked_at = NOW(), updated_at = NOW()
+              FROM songs s
+              WHERE j.id = (
+                  SELECT id FROM enrichment_jobs
+                  WHERE ((status = 'pending' AND run_after <= NOW())
+                     OR (status = 'running' AND locked_at < NOW() - $1 * INTERVAL '1 millisecond'))
+                    AND song_id IN (SELECT id FROM songs WHERE deleted_at IS NULL)
+                  ORDER BY run_after, id
+                  LIMIT 1
+                  FOR UPDATE SKIP LOCKED
+              ) AND s.id = j.song_id
+              RETURNING j.id, j.song_id, s."group", s.song, j.attempts`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var job models.EnrichmentJob
+	err := r.db.GetPool().QueryRow(ctx, query, lease.Milliseconds()).
+		Scan(&job.ID, &job.SongID, &job.Group, &job.Title, &job.Attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EnrichmentJob{}, ErrNoJobs
+		}
+		r.logger.Errorf("ClaimEnrichmentJob[repo]: Ошибка получения задачи: %v", err)
+		return models.EnrichmentJob{}, err
+	}
+
+	r.logger.Infof("ClaimEnrichmentJob[repo]: Получена задача: %+v", job)
+	return job, nil
+}
+
+// CompleteEnrichmentJob fills song with fetched details and marks its job as done
+// Song that was already updated by client in the meantime is left untouched. If song was moved to trash
+// while its details were fetched, job is returned to queue, so that song is enriched after it is restored
+func (r *Repo) CompleteEnrichmentJob(ctx context.Context, job models.EnrichmentJob, releaseDate time.Time, text, link string) error {
+	r.logger.Infof("CompleteEnrichmentJob[repo]: Завершение задачи ID: %d, песня ID: %d", job.ID, job.SongID)
+
+	query := `WITH updated AS (
+                  UPDATE songs SET release_date = $2, text = $3, link = $4, enrichment_status = 'done', updated_at = NOW()
+                  WHERE id = $5 AND enrichment_status = 'pending' AND deleted_at IS NULL
+              )
+              UPDATE enrichment_jobs SET status = CASE WHEN ` + awaitsRestore + ` THEN 'pending' ELSE 'done' END,
+                  last_error = NULL, locked_at = NULL, updated_at = NOW()
+              WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	if _, err := r.db.GetPool().Exec(ctx, query, job.ID, releaseDate, text, link, job.SongID); err != nil {
+		r.logger.Errorf("CompleteEnrichmentJob[repo]: Ошибка завершения задачи ID %d: %v", job.ID, err)
+		return err
+	}
+	return nil
+}
+
+// FailEnrichmentJob records failed attempt of job
+// Job is either scheduled for retry at retryAt or moved to dead state, marking song enrichment as failed.
+// Song in trash isn't marked, so instead of dead state its job is returned to queue until song is restored
+func (r *Repo) FailEnrichmentJob(ctx context.Context, job models.EnrichmentJob, errMsg string, retryAt time.Time, dead bool) error {
+	r.logger.Infof("FailEnrichmentJob[repo]: Ошибка задачи ID: %d, dead: %t, ошибка: %s", job.ID, dead, errMsg)
+
+	status := models.JobPending
+	if dead {
+		status = models.JobDead
+	}
+
+	query := `WITH updated AS (
+                  UPDATE songs SET enrichment_status = 'failed', updated_at = NOW()
+                  WHERE id = $5 AND $6 AND enrichment_status = 'pending' AND deleted_at IS NULL
+              )
+              UPDATE enrichment_jobs SET status = CASE WHEN ` + awaitsRestore + ` THEN 'pending' ELSE $2 END,
+                  last_error = $3, run_after = $4, locked_at = NULL, updated_at = NOW()
+              WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	if _, err := r.db.GetPool().Exec(ctx, query, job.ID, status, errMsg, retryAt, job.SongID, dead); err != nil {
+		r.logger.Errorf("FailEnrichmentJob[repo]: Ошибка обновления задачи ID %d: %v", job.ID, err)
+		return err
+	}
+	return nil
+}
+
+// GetEnrichment retrieves enrichment state of song by its ID. If song not found, returns ErrSongNotFound
+func (r *Repo) GetEnrichment(ctx context.Context, songID int) (models.SongEnrichment, error) {
+	r.logger.Infof("GetEnrichment[repo]: Получение статуса обогащения песни ID: %d", songID)
+
+	query := `SELECT s.id, s.enrichment_status, COALESCE(j.status, ''), COALESCE(j.attempts, 0),
+                     COALESCE(j.last_error, ''), CASE WHEN j.status = 'pending' THEN j.run_after END, j.updated_at
+              FROM songs s LEFT JOIN enrichment_jobs j ON j.song_id = s.id
+              WHERE s.id = $1 AND s.deleted_at IS NULL`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var enrichment models.SongEnrichment
+	err := r.db.GetPool().QueryRow(ctx, query, songID).
+		Scan(&enrichment.SongID, &enrichment.Status, &enrichment.JobStatus, &enrichment.Attempts,
+			&enrichment.LastError, &enrichment.NextAttemptAt, &enrichment.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("GetEnrichment[repo]: Песня с ID %d не найдена", songID)
+			return models.SongEnrichment{}, ErrSongNotFound
+		}
+		r.logger.Errorf("GetEnrichment[repo]: Ошибка получения статуса песни ID %d: %v", songID, err)
+		return models.SongEnrichment{}, err
+	}
+
+	return enrichment, nil
+}
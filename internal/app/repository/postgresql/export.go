@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+// exportField is column of songs which may be exported and field of song it is scanned into
+type exportField struct {
+	column string
+	dest   func(song *models.Song) interface{}
+}
+
+// exportFields maps names of exported columns to columns of songs
+var exportFields = map[string]exportField{
+	"id":                {`id`, func(s *models.Song) interface{} { return &s.ID }},
+	"artist_id":         {`artist_id`, func(s *models.Song) interface{} { return &s.ArtistID }},
+	"group":             {`"group"`, func(s *models.Song) interface{} { return &s.Group }},
+	"song":              {`song`, func(s *models.Song) interface{} { return &s.Title }},
+	"release_date":      {`release_date`, func(s *models.Song) interface{} { return &s.ReleaseDate }},
+	"text":              {`text`, func(s *models.Song) interface{} { return &s.Text }},
+	"link":              {`link`, func(s *models.Song) interface{} { return &s.Link }},
+	"enrichment_status": {`enrichment_status`, func(s *models.Song) interface{} { return &s.EnrichmentStatus }},
+	"version":           {`version`, func(s *models.Song) interface{} { return &s.Version }},
+	"created_at":        {`created_at`, func(s *models.Song) interface{} { return &s.CreatedAt }},
+	"updated_at":        {`updated_at`, func(s *models.Song) interface{} { return &s.UpdatedAt }},
+}
+
+// ExportSongs streams all songs matching filter in given order to fn, row by row as they are received
+// from database, so memory use doesn't depend on number of songs. Error returned by fn stops export.
+// Only given columns are selected, so that narrow export doesn't read text of songs; empty columns select all of them
+func (r *Repo) ExportSongs(ctx context.Context, filter models.SongFilters, sort models.SongSort, columns []string, fn func(models.Song) error) error {
+	r.logger.Infof("ExportSongs[repo]: Выгрузка песен с фильтром: %+v, сортировка: %s, колонки: %v", filter, sort, columns)
+
+	keys, err := songSortKeys(sort)
+	if err != nil {
+		r.logger.Errorf("ExportSongs[repo]: Неправильная сортировка: %v", err)
+		return err
+	}
+
+	selected := songColumns
+	var fields []exportField
+	if len(columns) > 0 {
+		names := make([]string, 0, len(columns))
+		for _, column := range columns {
+			field, ok := exportFields[column]
+			if !ok {
+				return fmt.Errorf("unknown export column: %s", column)
+			}
+			fields = append(fields, field)
+			names = append(names, field.column)
+		}
+		selected = strings.Join(names, `, `)
+	}
+
+	source, args := songSource(filter.AsOf, nil)
+	query := `SELECT ` + selected + ` FROM ` + source + ` WHERE deleted_at IS NULL`
+	conditions, args := songFilterConditions(filter, args)
+	query += conditions + orderByClause(keys, false)
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+	r.logger.Debugf("ExportSongs[repo]: SQL запрос: %s, параметры: %+v", query, args)
+
+	rows, err := r.db.GetPool().Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("ExportSongs[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return err
+	}
+	defer rows.Close()
+
+	var exported int
+	for rows.Next() {
+		var song models.Song
+		if fields == nil {
+			err = scanSong(rows, &song)
+		} else {
+			dest := make([]interface{}, len(fields))
+			for i, field := range fields {
+				dest[i] = field.dest(&song)
+			}
+			err = rows.Scan(dest...)
+		}
+		if err != nil {
+			r.logger.Errorf("ExportSongs[repo]: Ошибка сканирования строки: %v", err)
+			return err
+		}
+		if err = fn(song); err != nil {
+			return err
+		}
+		exported++
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("ExportSongs[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return rows.Err()
+	}
+
+	r.logger.Infof("ExportSongs[repo]: Выгружено %d песен", exported)
+	return nil
+}
@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"strconv"
+	"strings"
+
+	"rest-songs/internal/app/models"
+)
+
+// likeEscaper escapes LIKE wildcards, so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// songFilterConditions builds SQL conditions for filter, appending their parameters to args
+// Conditions are returned joined with AND, each preceded by " AND ", so they can follow other conditions of WHERE
+func songFilterConditions(filter models.SongFilters, args []interface{}) (string, []interface{}) {
+	var conditions string
+
+	if len(filter.Groups) > 0 {
+		patterns := make([]string, 0, len(filter.Groups))
+		for _, group := range filter.Groups {
+			patterns = append(patterns, likePattern(group, filter.Match))
+		}
+		args = append(args, patterns)
+		conditions += ` AND "group" ILIKE ANY(` + placeholder(args) + `)`
+	}
+
+	if filter.Title != "" {
+		args = append(args, likePattern(filter.Title, filter.Match))
+		conditions += ` AND song ILIKE ` + placeholder(args)
+	}
+
+	if !filter.ReleaseDate.IsZero() {
+		args = append(args, filter.ReleaseDate)
+		conditions += ` AND release_date = ` + placeholder(args)
+	}
+
+	if !filter.ReleaseFrom.IsZero() {
+		args = append(args, filter.ReleaseFrom)
+		conditions += ` AND release_date >= ` + placeholder(args)
+	}
+
+	if !filter.ReleaseTo.IsZero() {
+		// ReleaseTo is inclusive, so songs released during that whole day match
+		args = append(args, filter.ReleaseTo.AddDate(0, 0, 1))
+		conditions += ` AND release_date < ` + placeholder(args)
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		args = append(args, filter.CreatedAfter)
+		conditions += ` AND created_at > ` + placeholder(args)
+	}
+
+	if !filter.UpdatedAfter.IsZero() {
+		args = append(args, filter.UpdatedAfter)
+		conditions += ` AND updated_at > ` + placeholder(args)
+	}
+
+	return conditions, args
+}
+
+// likePattern builds ILIKE pattern for value according to match mode
+func likePattern(value, match string) string {
+	value = likeEscaper.Replace(value)
+	switch match {
+	case models.MatchPrefix:
+		return value + `%`
+	case models.MatchContains:
+		return `%` + value + `%`
+	default:
+		return value
+	}
+}
+
+// placeholder returns SQL placeholder of the last argument in args
+func placeholder(args []interface{}) string {
+	return `$` + strconv.Itoa(len(args))
+}
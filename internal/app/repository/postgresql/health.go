@@ -0,0 +1,52 @@
+package postgresql
+
+import (
+	"context"
+)
+
+// HealthRepository defines methods for checking state of database
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (int64, error)
+}
+
+// Ping checks that connection to database can be acquired and used
+func (r *Repo) Ping(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	return r.db.GetPool().Ping(ctx)
+}
+
+// MigrationVersion returns current schema version recorded by goose in goose_db_version table
+// Like goose itself, it takes the latest applied version which wasn't rolled back afterwards
+func (r *Repo) MigrationVersion(ctx context.Context) (int64, error) {
+	query := `SELECT version_id, is_applied FROM goose_db_version ORDER BY id DESC`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query)
+	if err != nil {
+		r.logger.Errorf("MigrationVersion[repo]: Ошибка получения версии миграций: %v", err)
+		return 0, err
+	}
+	defer rows.Close()
+
+	rolledBack := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		var applied bool
+		if err = rows.Scan(&version, &applied); err != nil {
+			r.logger.Errorf("MigrationVersion[repo]: Ошибка сканирования строки: %v", err)
+			return 0, err
+		}
+		if rolledBack[version] {
+			continue
+		}
+		if applied {
+			return version, nil
+		}
+		rolledBack[version] = true
+	}
+	return 0, rows.Err()
+}
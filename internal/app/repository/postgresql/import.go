@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/models"
+)
+
+// importColumns lists columns of temporary import table in order of copied rows
+var importColumns = []string{"group", "song", "release_date", "text", "link"}
+
+// SongImporter loads batches of songs within single transaction
+// Changes become visible only after Commit, Rollback discards them
+type SongImporter interface {
+	Load(ctx context.Context, songs []models.Song) (created, updated int64, err error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// songImport implements SongImporter: batches are copied into temporary table with COPY
+// and merged into songs from there
+type songImport struct {
+	tx      pgx.Tx
+	upsert  bool
+	timeout time.Duration
+	logger  *logrus.Logger
+}
+
+// BeginImport starts transaction of bulk import
+// With upsert set, song with the same group and title is updated instead of adding new one
+func (r *Repo) BeginImport(ctx context.Context, upsert bool) (SongImporter, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	tx, err := r.db.GetPool().Begin(ctx)
+	if err != nil {
+		r.logger.Errorf("BeginImport[repo]: Ошибка начала транзакции: %v", err)
+		return nil, err
+	}
+
+	query := `CREATE TEMP TABLE import_songs (
+                  "group" TEXT NOT NULL,
+                  song TEXT NOT NULL,
+                  release_date TIMESTAMPTZ NOT NULL,
+                  text TEXT NOT NULL,
+                  link TEXT NOT NULL
+              ) ON COMMIT DROP`
+	if _, err = tx.Exec(ctx, query); err != nil {
+		r.logger.Errorf("BeginImport[repo]: Ошибка создания временной таблицы: %v", err)
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	return &songImport{tx: tx, upsert: upsert, timeout: r.timeouts.Bulk, logger: r.logger}, nil
+}
+
+// Load copies batch of songs and merges it into songs table, creating missing artists
+// It returns number of created and updated songs
+func (i *songImport) Load(ctx context.Context, songs []models.Song) (int64, int64, error) {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	rows := make([][]interface{}, 0, len(songs))
+	for _, song := range songs {
+		rows = append(rows, []interface{}{song.Group, song.Title, song.ReleaseDate, song.Text, song.Link})
+	}
+	if _, err := i.tx.CopyFrom(ctx, pgx.Identifier{"import_songs"}, importColumns, pgx.CopyFromRows(rows)); err != nil {
+		i.logger.Errorf("Load[import]: Ошибка копирования песен: %v", err)
+		return 0, 0, err
+	}
+
+	query := `INSERT INTO artists (name)
+              SELECT DISTINCT ON (lower("group")) "group" FROM import_songs
+              ON CONFLICT ((lower(name))) DO NOTHING`
+	if _, err := i.tx.Exec(ctx, query); err != nil {
+		i.logger.Errorf("Load[import]: Ошибка создания групп: %v", err)
+		return 0, 0, err
+	}
+
+	var updated int64
+	if i.upsert {
+		// Pending enrichment of updated songs is cancelled, since song is given in full
+		query = `UPDATE enrichment_jobs j SET status = 'done', updated_at = NOW()
+                 FROM import_songs i
+                 JOIN artists a ON lower(a.name) = lower(i."group")
+                 JOIN songs s ON s.artist_id = a.id AND s.song = i.song AND s.deleted_at IS NULL
+                 WHERE j.song_id = s.id AND j.status = 'pending'`
+		if _, err := i.tx.Exec(ctx, query); err != nil {
+			i.logger.Errorf("Load[import]: Ошибка отмены обогащения: %v", err)
+			return 0, 0, err
+		}
+
+		query = `UPDATE songs s
+                 SET release_date = i.release_date, text = i.text, link = i.link,
+                     enrichment_status = 'done', updated_at = NOW()
+                 FROM import_songs i JOIN artists a ON lower(a.name) = lower(i."group")
+                 WHERE s.artist_id = a.id AND s.song = i.song AND s.deleted_at IS NULL`
+		result, err := i.tx.Exec(ctx, query)
+		if err != nil {
+			i.logger.Errorf("Load[import]: Ошибка обновления песен: %v", err)
+			return 0, 0, err
+		}
+		updated = result.RowsAffected()
+	}
+
+	query = `INSERT INTO songs (artist_id, "group", song, release_date, text, link, created_at, updated_at)
+             SELECT a.id, a.name, i.song, i.release_date, i.text, i.link, NOW(), NOW()
+             FROM import_songs i JOIN artists a ON lower(a.name) = lower(i."group")`
+	if i.upsert {
+		query += `
+             WHERE NOT EXISTS (
+                 SELECT 1 FROM songs s WHERE s.artist_id = a.id AND s.song = i.song AND s.deleted_at IS NULL
+             )`
+	}
+	result, err := i.tx.Exec(ctx, query)
+	if err != nil {
+		i.logger.Errorf("Load[import]: Ошибка добавления песен: %v", err)
+		return 0, 0, err
+	}
+
+	if _, err = i.tx.Exec(ctx, `TRUNCATE import_songs`); err != nil {
+		i.logger.Errorf("Load[import]: Ошибка очистки временной таблицы: %v", err)
+		return 0, 0, err
+	}
+
+	return result.RowsAffected(), updated, nil
+}
+
+// Commit applies all loaded batches
+func (i *songImport) Commit(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, i.timeout)
+	defer cancel()
+
+	return i.tx.Commit(ctx)
+}
+
+// Rollback discards all loaded batches
+// If ctx is already done, connection is closed, so transaction is rolled back by server
+func (i *songImport) Rollback(ctx context.Context) error {
+	return i.tx.Rollback(ctx)
+}
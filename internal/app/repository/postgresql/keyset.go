@@ -0,0 +1,281 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"rest-songs/internal/app/models"
+)
+
+// sortKey describes expression songs are ordered by
+// Cursor values of key are selected as text and cast back to sqlType when compared
+type sortKey struct {
+	expr    string
+	sqlType string
+	desc    bool
+}
+
+// defaultSongSort orders songs by release date, newest first
+// Songs without release date go last, id makes order total
+var defaultSongSort = []sortKey{
+	{expr: `COALESCE(release_date, '-infinity')`, sqlType: `timestamptz`, desc: true},
+	{expr: `id`, sqlType: `int`, desc: true},
+}
+
+// songSortColumns maps columns songs may be sorted by to their sort keys
+// Nullable columns are coalesced, so that keyset comparison works for every row
+var songSortColumns = map[string]sortKey{
+	models.SortByID:          {expr: `id`, sqlType: `int`},
+	models.SortByGroup:       {expr: `"group"`, sqlType: `text`},
+	models.SortByTitle:       {expr: `song`, sqlType: `text`},
+	models.SortByReleaseDate: {expr: `COALESCE(release_date, '-infinity')`, sqlType: `timestamptz`},
+	models.SortByCreatedAt:   {expr: `COALESCE(created_at, '-infinity')`, sqlType: `timestamptz`},
+	models.SortByUpdatedAt:   {expr: `COALESCE(updated_at, '-infinity')`, sqlType: `timestamptz`},
+}
+
+// timestamptzLayouts are text representations of timestamptz with ISO DateStyle,
+// offset of time zone is printed with minutes and seconds only when they are not zero
+var timestamptzLayouts = []string{
+	"2006-01-02 15:04:05.999999-07",
+	"2006-01-02 15:04:05.999999-07:00",
+	"2006-01-02 15:04:05.999999-07:00:00",
+}
+
+// validValue reports whether cursor value can be cast to type of key
+// Cursor comes from client, so its values are checked before they reach query
+func (k sortKey) validValue(value string) bool {
+	switch k.sqlType {
+	case `int`:
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	case `timestamptz`:
+		if value == "infinity" || value == "-infinity" {
+			return true
+		}
+		for _, layout := range timestamptzLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	default:
+		// Postgresql text can't hold zero byte or invalid UTF-8
+		return utf8.ValidString(value) && !strings.ContainsRune(value, 0)
+	}
+}
+
+// validCursor reports whether cursor has value of every key and fits order
+func validCursor(keys []sortKey, cursor models.Cursor, sort string) bool {
+	if len(cursor.Keys) != len(keys) || cursor.Sort != sort {
+		return false
+	}
+	for i, key := range keys {
+		if !key.validValue(cursor.Keys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortCollations maps collations of text columns to ICU collations of postgresql
+var sortCollations = map[string]string{
+	models.CollationRussian: `"ru-RU-x-icu"`,
+	models.CollationEnglish: `"en-US-x-icu"`,
+}
+
+// songSortKeys converts sort into sort keys, appending id as the final tiebreaker unless sort already has it
+// Empty sort results in defaultSongSort
+func songSortKeys(sort models.SongSort) ([]sortKey, error) {
+	if len(sort.Fields) == 0 {
+		return defaultSongSort, nil
+	}
+
+	collation, ok := sortCollations[sort.Collation]
+	if sort.Collation != "" && !ok {
+		return nil, fmt.Errorf("unknown collation: %s", sort.Collation)
+	}
+
+	keys := make([]sortKey, 0, len(sort.Fields)+1)
+	hasID := false
+	for _, field := range sort.Fields {
+		key, ok := songSortColumns[field.Column]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort column: %s", field.Column)
+		}
+		if key.sqlType == `text` && collation != "" {
+			key.expr += ` COLLATE ` + collation
+		}
+		key.desc = field.Desc
+		keys = append(keys, key)
+
+		if field.Column == models.SortByID {
+			hasID = true
+			break // id is unique, so further columns can't affect order
+		}
+	}
+
+	if !hasID {
+		keys = append(keys, songSortColumns[models.SortByID])
+	}
+	return keys, nil
+}
+
+// orderByClause builds ORDER BY clause for keys, reversing every direction if reverse is set
+func orderByClause(keys []sortKey, reverse bool) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc != reverse {
+			parts = append(parts, key.expr+` DESC`)
+		} else {
+			parts = append(parts, key.expr+` ASC`)
+		}
+	}
+	return ` ORDER BY ` + strings.Join(parts, `, `)
+}
+
+// keyColumns builds select list of key expressions converted to text, to be stored in cursor
+func keyColumns(keys []sortKey) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, `(`+key.expr+`)::text`)
+	}
+	return strings.Join(parts, `, `)
+}
+
+// keysetCondition builds condition selecting rows following cursor in order of keys,
+// or preceding it if cursor is backward, appending cursor values to args
+// For keys (a, b, c) it produces (a > $1) OR (a = $1 AND b > $2) OR (a = $1 AND b = $2 AND c > $3),
+// with comparison flipped for descending keys
+func keysetCondition(keys []sortKey, cursor models.Cursor, args []interface{}) (string, []interface{}) {
+	values := make([]string, 0, len(keys))
+	for i, key := range keys {
+		args = append(args, cursor.Keys[i])
+		values = append(values, placeholder(args)+`::`+key.sqlType)
+	}
+
+	alternatives := make([]string, 0, len(keys))
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].expr+` = `+values[j])
+		}
+
+		op := ` > `
+		if key.desc != cursor.Backward {
+			op = ` < `
+		}
+		parts = append(parts, key.expr+op+values[i])
+		alternatives = append(alternatives, `(`+strings.Join(parts, ` AND `)+`)`)
+	}
+
+	return ` AND (` + strings.Join(alternatives, ` OR `) + `)`, args
+}
+
+// GetWithCursor retrieves page of songs following or preceding cursor, based on filter criteria
+// in given order. Without cursor the first page is returned. Unlike offset pagination it neither skips nor repeats songs
+// when other songs are inserted or deleted between requests. If cursor doesn't fit order or has malformed values,
+// returns models.ErrInvalidCursor
+func (r *Repo) GetWithCursor(ctx context.Context, filter models.SongFilters, sort models.SongSort, cursor *models.Cursor, limit int) (models.Page[models.Song], error) {
+	r.logger.Infof("GetWithCursor[repo]: Получение песен с фильтром: %+v, сортировка: %s, курсор: %+v, лимит: %d",
+		filter, sort, cursor, limit)
+
+	keys, err := songSortKeys(sort)
+	if err != nil {
+		r.logger.Errorf("GetWithCursor[repo]: Неправильная сортировка: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+
+	sortStr := sort.String()
+	if cursor != nil && !validCursor(keys, *cursor, sortStr) {
+		r.logger.Warnf("GetWithCursor[repo]: Курсор не соответствует сортировке: %+v", cursor)
+		return models.Page[models.Song]{}, models.ErrInvalidCursor
+	}
+
+	source, args := songSource(filter.AsOf, nil)
+	query := `SELECT ` + songColumns + `, ` + keyColumns(keys) + ` FROM ` + source + ` WHERE deleted_at IS NULL`
+	conditions, args := songFilterConditions(filter, args)
+	query += conditions
+
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		conditions, args = keysetCondition(keys, *cursor, args)
+		query += conditions
+	}
+
+	// Select one extra row to find out whether there are more songs after the page
+	args = append(args, limit+1)
+	query += orderByClause(keys, backward) + ` LIMIT ` + placeholder(args)
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	r.logger.Debugf("GetWithCursor[repo]: SQL запрос: %s, параметры: %+v", query, args)
+
+	rows, err := r.db.GetPool().Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("GetWithCursor[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return models.Page[models.Song]{}, err
+	}
+	defer rows.Close()
+
+	songs := []models.Song{}
+	var rowKeys [][]string
+	for rows.Next() {
+		var song models.Song
+		values := make([]string, len(keys))
+		dest := make([]interface{}, len(keys))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = scanSong(rows, &song, dest...); err != nil {
+			r.logger.Errorf("GetWithCursor[repo]: Ошибка сканирования строки: %v", err)
+			return models.Page[models.Song]{}, err
+		}
+		songs = append(songs, song)
+		rowKeys = append(rowKeys, values)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetWithCursor[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return models.Page[models.Song]{}, rows.Err()
+	}
+
+	hasMore := len(songs) > limit
+	if hasMore {
+		songs = songs[:limit]
+		rowKeys = rowKeys[:limit]
+	}
+
+	// Rows of backward page are selected in reverse order
+	if backward {
+		for i, j := 0, len(songs)-1; i < j; i, j = i+1, j-1 {
+			songs[i], songs[j] = songs[j], songs[i]
+			rowKeys[i], rowKeys[j] = rowKeys[j], rowKeys[i]
+		}
+	}
+
+	page := models.Page[models.Song]{Items: songs, PageSize: limit}
+	if len(songs) > 0 {
+		first := &models.Cursor{Keys: rowKeys[0], Sort: sortStr, Backward: true}
+		last := &models.Cursor{Keys: rowKeys[len(rowKeys)-1], Sort: sortStr}
+		if backward {
+			page.NextCursor = last
+			if hasMore {
+				page.PrevCursor = first
+			}
+		} else {
+			if hasMore {
+				page.NextCursor = last
+			}
+			if cursor != nil {
+				page.PrevCursor = first
+			}
+		}
+	}
+
+	r.logger.Infof("GetWithCursor[repo]: Успешно получено %d песен", len(songs))
+	return page, nil
+}
@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"reflect"
+	"testing"
+
+	"rest-songs/internal/app/models"
+)
+
+func TestSortKeyValidValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		sqlType string
+		value   string
+		want    bool
+	}{
+		{name: "int", sqlType: `int`, value: "42", want: true},
+		{name: "negative int", sqlType: `int`, value: "-1", want: true},
+		{name: "int out of range", sqlType: `int`, value: "2147483648", want: false},
+		{name: "int with spaces", sqlType: `int`, value: " 1", want: false},
+		{name: "int injection", sqlType: `int`, value: "1 OR 1=1", want: false},
+		{name: "empty int", sqlType: `int`, value: "", want: false},
+		{name: "timestamptz", sqlType: `timestamptz`, value: "2024-10-01 12:30:00+03", want: true},
+		{name: "timestamptz with microseconds", sqlType: `timestamptz`, value: "2024-10-01 12:30:00.123456+00", want: true},
+		{name: "timestamptz with offset minutes", sqlType: `timestamptz`, value: "2024-10-01 12:30:00+05:30", want: true},
+		{name: "timestamptz with offset seconds", sqlType: `timestamptz`, value: "1890-01-01 00:00:00+02:30:17", want: true},
+		{name: "infinity", sqlType: `timestamptz`, value: "infinity", want: true},
+		{name: "minus infinity", sqlType: `timestamptz`, value: "-infinity", want: true},
+		{name: "RFC 3339", sqlType: `timestamptz`, value: "2024-10-01T12:30:00Z", want: false},
+		{name: "date only", sqlType: `timestamptz`, value: "2024-10-01", want: false},
+		{name: "invalid month", sqlType: `timestamptz`, value: "2024-13-01 00:00:00+00", want: false},
+		{name: "text", sqlType: `text`, value: "Сплин", want: true},
+		{name: "empty text", sqlType: `text`, value: "", want: true},
+		{name: "text with zero byte", sqlType: `text`, value: "a\x00b", want: false},
+		{name: "invalid UTF-8", sqlType: `text`, value: "a\xffb", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := sortKey{expr: `x`, sqlType: tt.sqlType}
+			if got := key.validValue(tt.value); got != tt.want {
+				t.Fatalf("validValue(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidCursor(t *testing.T) {
+	byGroup := models.SongSort{Fields: []models.SortField{{Column: models.SortByGroup}, {Column: models.SortByReleaseDate, Desc: true}}}
+
+	tests := []struct {
+		name   string
+		sort   models.SongSort
+		cursor models.Cursor
+		want   bool
+	}{
+		{name: "default sort", cursor: models.Cursor{Keys: []string{"-infinity", "3"}}, want: true},
+		{name: "sorted by group", sort: byGroup, cursor: models.Cursor{Keys: []string{"Muse", "2024-10-01 00:00:00+00", "3"}, Sort: "group,-release_date"}, want: true},
+		{name: "backward", sort: byGroup, cursor: models.Cursor{Keys: []string{"Muse", "infinity", "3"}, Sort: "group,-release_date", Backward: true}, want: true},
+		{name: "other sort", sort: byGroup, cursor: models.Cursor{Keys: []string{"Muse", "infinity", "3"}, Sort: "group"}, want: false},
+		{name: "cursor of default sort", sort: byGroup, cursor: models.Cursor{Keys: []string{"-infinity", "3"}}, want: false},
+		{name: "missing key", sort: byGroup, cursor: models.Cursor{Keys: []string{"Muse", "3"}, Sort: "group,-release_date"}, want: false},
+		{name: "extra key", cursor: models.Cursor{Keys: []string{"-infinity", "3", "4"}}, want: false},
+		{name: "malformed date", cursor: models.Cursor{Keys: []string{"yesterday", "3"}}, want: false},
+		{name: "malformed id", sort: byGroup, cursor: models.Cursor{Keys: []string{"Muse", "infinity", "three"}, Sort: "group,-release_date"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := songSortKeys(tt.sort)
+			if err != nil {
+				t.Fatalf("songSortKeys() error = %v", err)
+			}
+			if got := validCursor(keys, tt.cursor, tt.sort.String()); got != tt.want {
+				t.Fatalf("validCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCursorRoundTrip checks that cursor of page survives encoding and is accepted for the same order
+func TestCursorRoundTrip(t *testing.T) {
+	sort := models.SongSort{Fields: []models.SortField{{Column: models.SortByTitle, Desc: true}}, Collation: models.CollationRussian}
+	keys, err := songSortKeys(sort)
+	if err != nil {
+		t.Fatalf("songSortKeys() error = %v", err)
+	}
+
+	cursor := models.Cursor{Keys: []string{"Мороз по коже", "17"}, Sort: sort.String(), Backward: true}
+	text, err := cursor.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+	var decoded models.Cursor
+	if err = decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, cursor) {
+		t.Fatalf("UnmarshalText() = %+v, want %+v", decoded, cursor)
+	}
+	if !validCursor(keys, decoded, sort.String()) {
+		t.Fatalf("validCursor() rejected cursor %+v", decoded)
+	}
+
+	condition, args := keysetCondition(keys, decoded, []interface{}{"filter"})
+	wantCondition := ` AND ((song COLLATE "ru-RU-x-icu" > $2::text) OR (song COLLATE "ru-RU-x-icu" = $2::text AND id < $3::int))`
+	if condition != wantCondition {
+		t.Fatalf("keysetCondition() = %s, want %s", condition, wantCondition)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"filter", "Мороз по коже", "17"}) {
+		t.Fatalf("keysetCondition() args = %v", args)
+	}
+}
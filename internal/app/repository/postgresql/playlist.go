@@ -0,0 +1,407 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+var (
+	ErrPlaylistNotFound     = errors.New("playlist not found")
+	ErrPlaylistItemNotFound = errors.New("playlist item not found")
+)
+
+// playlistRankGap is distance between ranks of neighbouring items after renumbering
+const playlistRankGap = 1 << 16
+
+// playlistColumns lists columns of playlists table in order expected by scanPlaylist
+const playlistColumns = `id, name, description,
+    (SELECT COUNT(*) FROM playlist_items i JOIN songs s ON s.id = i.song_id AND s.deleted_at IS NULL
+     WHERE i.playlist_id = playlists.id),
+    created_at, updated_at`
+
+// visiblePlaylistItems selects items of playlist $1 which songs are not in trash
+const visiblePlaylistItems = `playlist_items i JOIN songs s ON s.id = i.song_id AND s.deleted_at IS NULL
+              WHERE i.playlist_id = $1`
+
+// PlaylistRepository defines methods for playlists and their entries
+// Entries of songs in trash are hidden everywhere, including positions, and are removed when song is purged
+type PlaylistRepository interface {
+	GetPlaylists(ctx context.Context, page, pageSize int) ([]models.Playlist, error)
+	CountPlaylists(ctx context.Context) (int64, error)
+	GetPlaylistById(ctx context.Context, id int) (models.Playlist, error)
+	GetPlaylistItems(ctx context.Context, id int) ([]models.PlaylistItem, error)
+	CreatePlaylist(ctx context.Context, name, description string) (models.Playlist, error)
+	UpdatePlaylist(ctx context.Context, id int, name, description string) (models.Playlist, error)
+	DeletePlaylist(ctx context.Context, id int) error
+	AddPlaylistItem(ctx context.Context, playlistID, songID, position int) (models.PlaylistItem, error)
+	MovePlaylistItem(ctx context.Context, playlistID, itemID, position int) (models.PlaylistItem, error)
+	RemovePlaylistItem(ctx context.Context, playlistID, itemID int) error
+}
+
+// GetPlaylists retrieves playlists ordered by name, supporting pagination
+func (r *Repo) GetPlaylists(ctx context.Context, page, pageSize int) ([]models.Playlist, error) {
+	r.logger.Infof("GetPlaylists[repo]: Получение плейлистов, страница: %d, размер страницы: %d", page, pageSize)
+
+	query := `SELECT ` + playlistColumns + ` FROM playlists ORDER BY lower(name), id LIMIT $1 OFFSET $2`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		r.logger.Errorf("GetPlaylists[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	playlists := []models.Playlist{}
+	for rows.Next() {
+		var playlist models.Playlist
+		if err = scanPlaylist(rows, &playlist); err != nil {
+			r.logger.Errorf("GetPlaylists[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetPlaylists[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+	return playlists, nil
+}
+
+// CountPlaylists returns number of playlists
+func (r *Repo) CountPlaylists(ctx context.Context) (int64, error) {
+	query := `SELECT COUNT(*) FROM playlists`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var total int64
+	if err := r.db.GetPool().QueryRow(ctx, query).Scan(&total); err != nil {
+		r.logger.Errorf("CountPlaylists[repo]: Ошибка подсчета плейлистов: %v", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetPlaylistById retrieves playlist by ID. If playlist not found, returns ErrPlaylistNotFound
+func (r *Repo) GetPlaylistById(ctx context.Context, id int) (models.Playlist, error) {
+	r.logger.Infof("GetPlaylistById[repo]: Получение плейлиста по ID: %d", id)
+
+	query := `SELECT ` + playlistColumns + ` FROM playlists WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var playlist models.Playlist
+	if err := scanPlaylist(r.db.GetPool().QueryRow(ctx, query, id), &playlist); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("GetPlaylistById[repo]: Плейлист с ID %d не найден", id)
+			return models.Playlist{}, ErrPlaylistNotFound
+		}
+		r.logger.Errorf("GetPlaylistById[repo]: Ошибка получения плейлиста по ID %d: %v", id, err)
+		return models.Playlist{}, err
+	}
+	return playlist, nil
+}
+
+// GetPlaylistItems retrieves entries of playlist in order with summaries of their songs
+func (r *Repo) GetPlaylistItems(ctx context.Context, id int) ([]models.PlaylistItem, error) {
+	query := `SELECT i.id, row_number() OVER (ORDER BY i.rank), i.added_at,
+                     s.id, s."group", s.song, s.release_date, s.link
+              FROM ` + visiblePlaylistItems + `
+              ORDER BY i.rank`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("GetPlaylistItems[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PlaylistItem{}
+	for rows.Next() {
+		var item models.PlaylistItem
+		err = rows.Scan(&item.ID, &item.Position, &item.AddedAt,
+			&item.Song.ID, &item.Song.Group, &item.Song.Title, &item.Song.ReleaseDate, &item.Song.Link)
+		if err != nil {
+			r.logger.Errorf("GetPlaylistItems[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetPlaylistItems[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+	return items, nil
+}
+
+// CreatePlaylist inserts new empty playlist into database
+func (r *Repo) CreatePlaylist(ctx context.Context, name, description string) (models.Playlist, error) {
+	r.logger.Infof("CreatePlaylist[repo]: Создание плейлиста: %s", name)
+
+	query := `INSERT INTO playlists (name, description, created_at, updated_at) VALUES ($1, $2, NOW(), NOW())
+              RETURNING ` + playlistColumns
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var playlist models.Playlist
+	if err := scanPlaylist(r.db.GetPool().QueryRow(ctx, query, name, description), &playlist); err != nil {
+		r.logger.Errorf("CreatePlaylist[repo]: Ошибка создания плейлиста %s: %v", name, err)
+		return models.Playlist{}, err
+	}
+
+	r.logger.Infof("CreatePlaylist[repo]: Успешно создан плейлист: %+v", playlist)
+	return playlist, nil
+}
+
+// UpdatePlaylist sets name and description of playlist
+func (r *Repo) UpdatePlaylist(ctx context.Context, id int, name, description string) (models.Playlist, error) {
+	r.logger.Infof("UpdatePlaylist[repo]: Обновление плейлиста ID: %d", id)
+
+	query := `UPDATE playlists SET name = $2, description = $3, updated_at = NOW() WHERE id = $1
+              RETURNING ` + playlistColumns
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var playlist models.Playlist
+	if err := scanPlaylist(r.db.GetPool().QueryRow(ctx, query, id, name, description), &playlist); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("UpdatePlaylist[repo]: Плейлист с ID %d не найден", id)
+			return models.Playlist{}, ErrPlaylistNotFound
+		}
+		r.logger.Errorf("UpdatePlaylist[repo]: Ошибка обновления плейлиста ID %d: %v", id, err)
+		return models.Playlist{}, err
+	}
+	return playlist, nil
+}
+
+// DeletePlaylist removes playlist with all its entries
+func (r *Repo) DeletePlaylist(ctx context.Context, id int) error {
+	r.logger.Infof("DeletePlaylist[repo]: Удаление плейлиста по ID: %d", id)
+
+	query := `DELETE FROM playlists WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("DeletePlaylist[repo]: Ошибка удаления плейлиста по ID %d: %v", id, err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		r.logger.Warnf("DeletePlaylist[repo]: Плейлист с ID %d не найден для удаления", id)
+		return ErrPlaylistNotFound
+	}
+	return nil
+}
+
+// AddPlaylistItem inserts song into playlist at 1-based position, position 0 or past the end appends it
+// Song in trash can't be added, in that case ErrSongNotFound is returned
+func (r *Repo) AddPlaylistItem(ctx context.Context, playlistID, songID, position int) (models.PlaylistItem, error) {
+	r.logger.Infof("AddPlaylistItem[repo]: Добавление песни ID %d в плейлист ID %d на позицию %d", songID, playlistID, position)
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var item models.PlaylistItem
+	err := r.inPlaylistTx(ctx, playlistID, func(tx pgx.Tx) error {
+		query := `SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR SHARE`
+		if err := tx.QueryRow(ctx, query, songID).Scan(&songID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrSongNotFound
+			}
+			return err
+		}
+
+		rank, err := r.playlistRank(ctx, tx, playlistID, 0, position)
+		if err != nil {
+			return err
+		}
+
+		query = `INSERT INTO playlist_items (playlist_id, song_id, rank, added_at) VALUES ($1, $2, $3, NOW())
+                 RETURNING id`
+		if err = tx.QueryRow(ctx, query, playlistID, songID, rank).Scan(&item.ID); err != nil {
+			return err
+		}
+
+		item, err = playlistItem(ctx, tx, playlistID, item.ID)
+		return err
+	})
+	if err != nil {
+		r.logger.Errorf("AddPlaylistItem[repo]: Ошибка добавления песни ID %d в плейлист ID %d: %v", songID, playlistID, err)
+		return models.PlaylistItem{}, err
+	}
+	return item, nil
+}
+
+// MovePlaylistItem moves entry of playlist to 1-based position, position past the end moves it to the end
+// Only rank of moved entry is changed, unless there is no gap left between its new neighbours
+func (r *Repo) MovePlaylistItem(ctx context.Context, playlistID, itemID, position int) (models.PlaylistItem, error) {
+	r.logger.Infof("MovePlaylistItem[repo]: Перемещение элемента ID %d плейлиста ID %d на позицию %d", itemID, playlistID, position)
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var item models.PlaylistItem
+	err := r.inPlaylistTx(ctx, playlistID, func(tx pgx.Tx) error {
+		query := `SELECT i.id FROM ` + visiblePlaylistItems + ` AND i.id = $2`
+		if err := tx.QueryRow(ctx, query, playlistID, itemID).Scan(&itemID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPlaylistItemNotFound
+			}
+			return err
+		}
+
+		rank, err := r.playlistRank(ctx, tx, playlistID, itemID, position)
+		if err != nil {
+			return err
+		}
+
+		query = `UPDATE playlist_items SET rank = $2 WHERE id = $1`
+		if _, err = tx.Exec(ctx, query, itemID, rank); err != nil {
+			return err
+		}
+
+		item, err = playlistItem(ctx, tx, playlistID, itemID)
+		return err
+	})
+	if err != nil {
+		r.logger.Errorf("MovePlaylistItem[repo]: Ошибка перемещения элемента ID %d плейлиста ID %d: %v", itemID, playlistID, err)
+		return models.PlaylistItem{}, err
+	}
+	return item, nil
+}
+
+// RemovePlaylistItem removes entry from playlist, positions of entries after it shift by one
+func (r *Repo) RemovePlaylistItem(ctx context.Context, playlistID, itemID int) error {
+	r.logger.Infof("RemovePlaylistItem[repo]: Удаление элемента ID %d плейлиста ID %d", itemID, playlistID)
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	err := r.inPlaylistTx(ctx, playlistID, func(tx pgx.Tx) error {
+		query := `DELETE FROM playlist_items WHERE id = $2 AND playlist_id = $1`
+		result, err := tx.Exec(ctx, query, playlistID, itemID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return ErrPlaylistItemNotFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrPlaylistItemNotFound) && !errors.Is(err, ErrPlaylistNotFound) {
+		r.logger.Errorf("RemovePlaylistItem[repo]: Ошибка удаления элемента ID %d плейлиста ID %d: %v", itemID, playlistID, err)
+	}
+	return err
+}
+
+// inPlaylistTx runs fn in transaction holding lock of playlist, so changes of one playlist are serialized
+// Playlist is marked as updated when fn succeeds
+func (r *Repo) inPlaylistTx(ctx context.Context, playlistID int, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.GetPool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE playlists SET updated_at = NOW() WHERE id = $1`
+	result, err := tx.Exec(ctx, query, playlistID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPlaylistNotFound
+	}
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// playlistRank returns rank that puts entry at 1-based position among visible entries of playlist
+// Entry itemID, if it is non-zero, is moved, so it is not counted. New rank lies between rank of visible entry
+// preceding the position and rank of the next entry, hidden or not, so it never collides with existing ranks.
+// When they are adjacent, playlist is renumbered with playlistRankGap between entries
+func (r *Repo) playlistRank(ctx context.Context, tx pgx.Tx, playlistID, itemID, position int) (int64, error) {
+	for renumbered := false; ; renumbered = true {
+		prev, next, err := playlistNeighbours(ctx, tx, playlistID, itemID, position)
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case prev == nil && next == nil:
+			return playlistRankGap, nil
+		case next == nil:
+			return *prev + playlistRankGap, nil
+		case prev == nil:
+			return *next - playlistRankGap, nil
+		case *next-*prev > 1:
+			return *prev + (*next-*prev)/2, nil
+		case renumbered:
+			return 0, errors.New("no gap between ranks after renumbering")
+		}
+
+		r.logger.Infof("playlistRank[repo]: Перенумерация плейлиста ID %d", playlistID)
+		query := `UPDATE playlist_items i SET rank = n.number * $2
+                  FROM (SELECT id, row_number() OVER (ORDER BY rank) AS number FROM playlist_items WHERE playlist_id = $1) n
+                  WHERE i.id = n.id`
+		if _, err := tx.Exec(ctx, query, playlistID, playlistRankGap); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// playlistNeighbours returns ranks of entries the entry at 1-based position goes between
+// Previous rank is nil at the start of playlist and next rank is nil at the end
+func playlistNeighbours(ctx context.Context, tx pgx.Tx, playlistID, itemID, position int) (*int64, *int64, error) {
+	var prev, next *int64
+	if position > 1 {
+		query := `SELECT i.rank FROM ` + visiblePlaylistItems + ` AND i.id <> $2
+                  ORDER BY i.rank LIMIT 1 OFFSET $3`
+		err := tx.QueryRow(ctx, query, playlistID, itemID, position-2).Scan(&prev)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Position is past the end of playlist
+			position = 0
+		} else if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if position <= 0 {
+		query := `SELECT MAX(rank) FROM playlist_items WHERE playlist_id = $1 AND id <> $2`
+		err := tx.QueryRow(ctx, query, playlistID, itemID).Scan(&prev)
+		return prev, nil, err
+	}
+
+	query := `SELECT MIN(rank) FROM playlist_items WHERE playlist_id = $1 AND id <> $2
+              AND ($3::bigint IS NULL OR rank > $3)`
+	err := tx.QueryRow(ctx, query, playlistID, itemID, prev).Scan(&next)
+	return prev, next, err
+}
+
+// playlistItem retrieves visible entry of playlist with its position
+func playlistItem(ctx context.Context, tx pgx.Tx, playlistID, itemID int) (models.PlaylistItem, error) {
+	query := `SELECT id, position, added_at, song_id, "group", song, release_date, link FROM (
+                  SELECT i.id, row_number() OVER (ORDER BY i.rank) AS position, i.added_at,
+                         s.id AS song_id, s."group", s.song, s.release_date, s.link
+                  FROM ` + visiblePlaylistItems + `
+              ) items WHERE id = $2`
+
+	var item models.PlaylistItem
+	err := tx.QueryRow(ctx, query, playlistID, itemID).Scan(&item.ID, &item.Position, &item.AddedAt,
+		&item.Song.ID, &item.Song.Group, &item.Song.Title, &item.Song.ReleaseDate, &item.Song.Link)
+	return item, err
+}
+
+// scanPlaylist scans row selected with playlistColumns into playlist
+func scanPlaylist(row pgx.Row, playlist *models.Playlist) error {
+	return row.Scan(&playlist.ID, &playlist.Name, &playlist.Description, &playlist.ItemsCount,
+		&playlist.CreatedAt, &playlist.UpdatedAt)
+}
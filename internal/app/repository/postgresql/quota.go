@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// QuotaRepository defines methods for daily request quotas of clients
+type QuotaRepository interface {
+	ConsumeQuota(ctx context.Context, client, class string, day time.Time, limit int) (int64, bool, error)
+	PurgeQuotas(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ConsumeQuota counts request of client against daily quota of request class
+// It returns number of requests used that day and whether request fits into limit.
+// Rejected requests are not counted, so used never exceeds limit
+func (r *Repo) ConsumeQuota(ctx context.Context, client, class string, day time.Time, limit int) (int64, bool, error) {
+	query := `INSERT INTO rate_limit_quotas AS q (client, class, day, used) VALUES ($1, $2, $3, 1)
+              ON CONFLICT (client, class, day) DO UPDATE SET used = q.used + 1 WHERE q.used < $4
+              RETURNING used`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var used int64
+	if err := r.db.GetPool().QueryRow(ctx, query, client, class, day, limit).Scan(&used); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return int64(limit), false, nil
+		}
+		r.logger.Errorf("ConsumeQuota[repo]: Ошибка учета квоты клиента %s: %v", client, err)
+		return 0, false, err
+	}
+	return used, true, nil
+}
+
+// PurgeQuotas removes counters of days before given one and returns number of removed rows
+func (r *Repo) PurgeQuotas(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM rate_limit_quotas WHERE day < $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, before)
+	if err != nil {
+		r.logger.Errorf("PurgeQuotas[repo]: Ошибка удаления старых квот: %v", err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
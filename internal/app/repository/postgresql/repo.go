@@ -2,8 +2,11 @@ package postgresql
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"strconv"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
@@ -11,67 +14,127 @@ import (
 	"rest-songs/internal/app/repository/database"
 )
 
-var ErrSongNotFound = errors.New("song not found")
+var (
+	ErrSongNotFound    = errors.New("song not found")
+	ErrVersionMismatch = errors.New("song version mismatch")
+)
+
+// songColumns lists columns of songs table in order expected by scanSong
+const songColumns = `id, artist_id, "group", song, release_date, text, link, enrichment_status, version, created_at, updated_at, deleted_at`
+
+// upsertArtist is CTE which finds artist by name given in $1 or creates new one
+// Song "group" column is then set from artist name, so it follows canonical spelling
+const upsertArtist = `artist AS (
+                  INSERT INTO artists (name) VALUES ($1)
+                  ON CONFLICT ((lower(name))) DO UPDATE SET name = artists.name
+                  RETURNING id, name
+              )`
+
+// upsertTargetArtist is upsertArtist for modification of song selected by targetSong CTE
+// Artist is created only if song is found, so rejected modification doesn't leave artist without songs
+const upsertTargetArtist = `artist AS (
+                  INSERT INTO artists (name) SELECT $1::text WHERE EXISTS (SELECT 1 FROM target)
+                  ON CONFLICT ((lower(name))) DO UPDATE SET name = artists.name
+                  RETURNING id, name
+              )`
+
+// targetSong returns CTE which locks song to be modified, if it is not in trash and has expected version
+func targetSong(idArg, versionArg string) string {
+	return `target AS (
+                 SELECT id FROM songs
+                 WHERE id = ` + idArg + ` AND deleted_at IS NULL AND (` + versionArg + `::int = 0 OR version = ` + versionArg + `)
+                 FOR UPDATE
+             )`
+}
 
 // Repository interface defines methods for interacting with songs in database
 type Repository interface {
-	GetWithFilter(filter models.SongFilters, page, pageSize int) ([]models.Song, error)
-	GetById(id int) (models.Song, error)
-	Update(id int, song models.Song) (models.Song, error)
-	Delete(id int) error
-	Create(song models.Song) (models.Song, error)
+	GetWithFilter(ctx context.Context, filter models.SongFilters, sort models.SongSort, page, pageSize int) ([]models.Song, error)
+	GetWithCursor(ctx context.Context, filter models.SongFilters, sort models.SongSort, cursor *models.Cursor, limit int) (models.Page[models.Song], error)
+	Count(ctx context.Context, filter models.SongFilters, estimate bool) (int64, error)
+	GetById(ctx context.Context, id int) (models.Song, error)
+	GetByIdAsOf(ctx context.Context, id int, asOf time.Time) (models.Song, error)
+	Update(ctx context.Context, id int, song models.Song, expectedVersion int) (models.Song, error)
+	Patch(ctx context.Context, id int, patch models.SongPatch, expectedVersion int) (models.Song, error)
+	Delete(ctx context.Context, id int, expectedVersion int) error
+	Create(ctx context.Context, song models.Song) (models.Song, error)
+	BeginImport(ctx context.Context, upsert bool) (SongImporter, error)
+	ExportSongs(ctx context.Context, filter models.SongFilters, sort models.SongSort, columns []string, fn func(models.Song) error) error
+
+	EnrichmentRepository
+	ArtistRepository
+	TrashRepository
+	RevisionRepository
+	SyncedLyricsRepository
+	ChordSheetRepository
+	PlaylistRepository
+	APIKeyRepository
+	QuotaRepository
+	HealthRepository
+	Search(ctx context.Context, search models.SongSearch, filter models.SongFilters, page, pageSize int) ([]models.SongSearchResult, error)
+}
+
+// Timeouts holds deadlines of database queries by kind of operation
+// Zero timeout means that query is limited only by context of caller
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+	Bulk  time.Duration // Import, export and purge of many rows
 }
 
 // Repo struct implements Repository interface and interacts with postgresql database using connection pool
 type Repo struct {
-	db     database.Database
-	logger *logrus.Logger
+	db       database.Database
+	timeouts Timeouts
+	logger   *logrus.Logger
 }
 
-// New creates new Repo instance, taking database connection pool and logger as parameters
-func New(db database.Database, logger *logrus.Logger) *Repo {
+// New creates new Repo instance, taking database connection pool, query Timeouts and logger as parameters
+func New(db database.Database, timeouts Timeouts, logger *logrus.Logger) *Repo {
 	return &Repo{
-		db:     db,
-		logger: logger,
+		db:       db,
+		timeouts: timeouts,
+		logger:   logger,
 	}
 }
 
-// GetWithFilter retrieves songs from database based on the provided filter criteria,
-// supporting pagination. It returns slice of Song models and error if any occurs
-func (r *Repo) GetWithFilter(filter models.SongFilters, page, pageSize int) ([]models.Song, error) {
-	r.logger.Infof("GetWithFilter[repo]: Получение песен с фильтром: %+v, страница: %d, размер страницы: %d", filter, page, pageSize)
-
-	query := `SELECT id, "group", song, release_date, text, link, created_at, updated_at 
-           FROM songs WHERE 1=1` // Where 1=1 for filtering logic, so that further conditions also consider
+// withTimeout returns context of query derived from ctx of caller and limited by timeout
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
 
-	var songs []models.Song
-	var args []interface{}
-	argIndex := 1
+// GetWithFilter retrieves songs from database based on the provided filter criteria in given order,
+// supporting pagination. It returns slice of Song models and error if any occurs
+func (r *Repo) GetWithFilter(ctx context.Context, filter models.SongFilters, sort models.SongSort, page, pageSize int) ([]models.Song, error) {
+	r.logger.Infof("GetWithFilter[repo]: Получение песен с фильтром: %+v, сортировка: %s, страница: %d, размер страницы: %d",
+		filter, sort, page, pageSize)
 
-	if filter.Group != "" {
-		query += ` AND "group" = $` + strconv.Itoa(argIndex)
-		args = append(args, filter.Group)
-		argIndex++
+	keys, err := songSortKeys(sort)
+	if err != nil {
+		r.logger.Errorf("GetWithFilter[repo]: Неправильная сортировка: %v", err)
+		return nil, err
 	}
 
-	if filter.Title != "" {
-		query += ` AND song = $` + strconv.Itoa(argIndex)
-		args = append(args, filter.Title)
-		argIndex++
-	}
+	source, args := songSource(filter.AsOf, nil)
+	query := `SELECT ` + songColumns + `
+           FROM ` + source + ` WHERE deleted_at IS NULL` // Songs in trash are never listed, further conditions follow with AND
 
-	if !filter.ReleaseDate.IsZero() {
-		query += ` AND release_date = $` + strconv.Itoa(argIndex)
-		args = append(args, filter.ReleaseDate)
-		argIndex++
-	}
+	songs := []models.Song{}
+	conditions, args := songFilterConditions(filter, args)
+	query += conditions
 
 	// Add pagination
 	offset := (page - 1) * pageSize
-	query += ` ORDER BY release_date DESC LIMIT $` + strconv.Itoa(argIndex) + ` OFFSET $` + strconv.Itoa(argIndex+1)
-	args = append(args, pageSize, offset)
+	args = append(args, pageSize)
+	query += orderByClause(keys, false) + ` LIMIT ` + placeholder(args)
+	args = append(args, offset)
+	query += ` OFFSET ` + placeholder(args)
 
-	ctx := context.Background()
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
 	r.logger.Debugf("GetWithFilter[repo]: SQL запрос: %s, параметры: %+v", query, args)
 
 	// Execute query and iterate over result rows
@@ -85,9 +148,7 @@ func (r *Repo) GetWithFilter(filter models.SongFilters, page, pageSize int) ([]m
 	// Scan each row into Song object and append to songs slice
 	for rows.Next() {
 		var song models.Song
-		err = rows.Scan(&song.ID, &song.Group, &song.Title, &song.ReleaseDate,
-			&song.Text, &song.Link, &song.CreatedAt, &song.UpdatedAt)
-		if err != nil {
+		if err = scanSong(rows, &song); err != nil {
 			r.logger.Errorf("GetWithFilter[repo]: Ошибка сканирования строки: %v", err)
 			return nil, err
 		}
@@ -104,17 +165,59 @@ func (r *Repo) GetWithFilter(filter models.SongFilters, page, pageSize int) ([]m
 	return songs, nil
 }
 
-// GetById retrieves song by ID from database. If song not found, returns ErrSongNotFound
-func (r *Repo) GetById(id int) (models.Song, error) {
+// Count returns number of songs matching filter
+// With estimate set, number is taken from query planner statistics instead of scanning table
+func (r *Repo) Count(ctx context.Context, filter models.SongFilters, estimate bool) (int64, error) {
+	r.logger.Infof("Count[repo]: Подсчет песен с фильтром: %+v, оценка: %t", filter, estimate)
+
+	source, args := songSource(filter.AsOf, nil)
+	conditions, args := songFilterConditions(filter, args)
+	query := `SELECT COUNT(*) FROM ` + source + ` WHERE deleted_at IS NULL` + conditions
+	if estimate {
+		query = `EXPLAIN (FORMAT JSON) SELECT 1 FROM ` + source + ` WHERE deleted_at IS NULL` + conditions
+	}
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	if !estimate {
+		var total int64
+		if err := r.db.GetPool().QueryRow(ctx, query, args...).Scan(&total); err != nil {
+			r.logger.Errorf("Count[repo]: Ошибка подсчета песен: %v", err)
+			return 0, err
+		}
+		return total, nil
+	}
+
+	var planJSON string
+	if err := r.db.GetPool().QueryRow(ctx, query, args...).Scan(&planJSON); err != nil {
+		r.logger.Errorf("Count[repo]: Ошибка получения плана запроса: %v", err)
+		return 0, err
+	}
+
+	var plan []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil || len(plan) == 0 {
+		r.logger.Errorf("Count[repo]: Ошибка разбора плана запроса: %v", err)
+		return 0, fmt.Errorf("unexpected query plan: %s", planJSON)
+	}
+
+	return int64(plan[0].Plan.PlanRows), nil
+}
+
+// GetById retrieves song by ID from database. If song not found or is in trash, returns ErrSongNotFound
+func (r *Repo) GetById(ctx context.Context, id int) (models.Song, error) {
 	r.logger.Infof("GetById[repo]: Получение песни по ID: %d", id)
 
-	query := `SELECT id, "group", song, release_date, text, link, created_at, updated_at FROM songs WHERE id = $1`
+	query := `SELECT ` + songColumns + ` FROM songs WHERE id = $1 AND deleted_at IS NULL`
 	var song models.Song
-	ctx := context.Background()
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
 
 	// Execute query and scan result into Song object
-	err := r.db.GetPool().QueryRow(ctx, query, id).
-		Scan(&song.ID, &song.Group, &song.Title, &song.ReleaseDate, &song.Text, &song.Link, &song.CreatedAt, &song.UpdatedAt)
+	err := scanSong(r.db.GetPool().QueryRow(ctx, query, id), &song)
 	if err != nil {
 		// If no rows returned, return ErrSongNotFound.
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -130,22 +233,34 @@ func (r *Repo) GetById(id int) (models.Song, error) {
 }
 
 // Update modifies existing song in database by ID, and returns updated song
+// Since song is given in full, its pending enrichment job is cancelled
+// If expectedVersion is not 0, song is updated only if its version matches, otherwise ErrVersionMismatch is returned
 // If song with given ID not found, returns ErrSongNotFound
-func (r *Repo) Update(id int, song models.Song) (models.Song, error) {
+func (r *Repo) Update(ctx context.Context, id int, song models.Song, expectedVersion int) (models.Song, error) {
 	r.logger.Infof("Update[repo]: Обновление песни по ID: %d, данные: %+v", id, song)
 
-	query := `UPDATE songs SET "group" = $1, song = $2, release_date = $3, text = $4, link = $5, updated_at = NOW() 
-             WHERE id = $6 RETURNING id, "group", song, release_date, text, link, created_at, updated_at`
-	ctx := context.Background()
+	// Job is cancelled only together with successful update, rejected update leaves it queued
+	query := `WITH ` + targetSong(`$6`, `$7`) + `, ` + upsertTargetArtist + `, updated AS (
+                 UPDATE songs SET artist_id = (SELECT id FROM artist), "group" = (SELECT name FROM artist),
+                     song = $2, release_date = $3, text = $4, link = $5, enrichment_status = 'done', updated_at = NOW()
+                 WHERE id IN (SELECT id FROM target)
+                 RETURNING ` + songColumns + `
+             ), cancelled AS (
+                 UPDATE enrichment_jobs SET status = 'done', updated_at = NOW()
+                 WHERE song_id IN (SELECT id FROM updated) AND status = 'pending'
+             )
+             SELECT ` + songColumns + ` FROM updated`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
 
 	// Execute query and scan result into song object
-	err := r.db.GetPool().QueryRow(ctx, query, song.Group, song.Title, song.ReleaseDate, song.Text, song.Link, id).
-		Scan(&song.ID, &song.Group, &song.Title, &song.ReleaseDate, &song.Text, &song.Link, &song.CreatedAt, &song.UpdatedAt)
+	err := scanSong(r.db.GetPool().QueryRow(ctx, query,
+		song.Group, song.Title, song.ReleaseDate, song.Text, song.Link, id, expectedVersion), &song)
 	if err != nil {
-		// If no rows returned, return ErrSongNotFound
+		// If no rows returned, song is either missing or has another version
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.Warnf("Update[repo]: Песня с ID %d не найдена для обновления", id)
-			return models.Song{}, ErrSongNotFound
+			r.logger.Warnf("Update[repo]: Песня с ID %d и версией %d не найдена для обновления", id, expectedVersion)
+			return models.Song{}, r.missingOrConflict(ctx, id, expectedVersion)
 		}
 		r.logger.Errorf("Update[repo]: Ошибка обновления песни по ID %d: %v", id, err)
 		return models.Song{}, err
@@ -155,42 +270,132 @@ func (r *Repo) Update(id int, song models.Song) (models.Song, error) {
 	return song, nil
 }
 
-// Delete removes song from database by ID
+// Patch updates only fields of song set in patch and returns updated song
+// If patch sets release date, text or link, pending enrichment of song is cancelled, so that background worker
+// doesn't overwrite them with fetched details. Empty patch doesn't modify song. If expectedVersion is not 0,
+// song is updated only if its version matches, otherwise ErrVersionMismatch is returned.
 // If song with given ID not found, returns ErrSongNotFound
-func (r *Repo) Delete(id int) error {
-	r.logger.Infof("Delete[repo]: Удаление песни по ID: %d", id)
+func (r *Repo) Patch(ctx context.Context, id int, patch models.SongPatch, expectedVersion int) (models.Song, error) {
+	r.logger.Infof("Patch[repo]: Частичное обновление песни по ID: %d, версия: %d", id, expectedVersion)
+
+	if patch.IsEmpty() {
+		song, err := r.GetById(ctx, id)
+		if err == nil && expectedVersion != 0 && song.Version != expectedVersion {
+			return models.Song{}, ErrVersionMismatch
+		}
+		return song, err
+	}
 
-	query := `DELETE FROM songs WHERE id = $1`
-	ctx := context.Background()
+	var with []string
+	var sets []string
+	var args []interface{}
+
+	// Group goes first, since upsertTargetArtist expects it in $1
+	if patch.Group != nil {
+		args = append(args, *patch.Group)
+		with = append(with, upsertTargetArtist)
+		sets = append(sets, `artist_id = (SELECT id FROM artist)`, `"group" = (SELECT name FROM artist)`)
+	}
+	if patch.Title != nil {
+		args = append(args, *patch.Title)
+		sets = append(sets, `song = `+placeholder(args))
+	}
+	if patch.ReleaseDate != nil {
+		args = append(args, *patch.ReleaseDate)
+		sets = append(sets, `release_date = `+placeholder(args))
+	}
+	if patch.Text != nil {
+		args = append(args, *patch.Text)
+		sets = append(sets, `text = `+placeholder(args))
+	}
+	if patch.Link != nil {
+		args = append(args, *patch.Link)
+		sets = append(sets, `link = `+placeholder(args))
+	}
+
+	if patch.ReleaseDate != nil || patch.Text != nil || patch.Link != nil {
+		sets = append(sets, `enrichment_status = 'done'`)
+	}
+
+	args = append(args, id)
+	idArg := placeholder(args)
+	args = append(args, expectedVersion)
+	versionArg := placeholder(args)
+	with = append([]string{targetSong(idArg, versionArg)}, with...)
+	with = append(with, `updated AS (
+                 UPDATE songs SET `+strings.Join(sets, `, `)+`, updated_at = NOW()
+                 WHERE id IN (SELECT id FROM target)
+                 RETURNING `+songColumns+`
+             )`)
+	// Job is cancelled only together with successful update, rejected patch leaves it queued
+	if patch.ReleaseDate != nil || patch.Text != nil || patch.Link != nil {
+		with = append(with, `cancelled AS (
+                 UPDATE enrichment_jobs SET status = 'done', updated_at = NOW()
+                 WHERE song_id IN (SELECT id FROM updated) AND status = 'pending'
+             )`)
+	}
+	query := `WITH ` + strings.Join(with, `, `) + `
+             SELECT ` + songColumns + ` FROM updated`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	r.logger.Debugf("Patch[repo]: SQL запрос: %s", query)
+
+	var song models.Song
+	if err := scanSong(r.db.GetPool().QueryRow(ctx, query, args...), &song); err != nil {
+		// If no rows returned, song is either missing or has another version
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("Patch[repo]: Песня с ID %d и версией %d не найдена для обновления", id, expectedVersion)
+			return models.Song{}, r.missingOrConflict(ctx, id, expectedVersion)
+		}
+		r.logger.Errorf("Patch[repo]: Ошибка обновления песни по ID %d: %v", id, err)
+		return models.Song{}, err
+	}
+
+	r.logger.Infof("Patch[repo]: Успешно обновлена песня: %+v", song)
+	return song, nil
+}
+
+// Delete moves song to trash by ID, it is removed from database by Purge later
+// If expectedVersion is not 0, song is moved only if its version matches, otherwise ErrVersionMismatch is returned
+// If song with given ID not found, returns ErrSongNotFound
+func (r *Repo) Delete(ctx context.Context, id int, expectedVersion int) error {
+	r.logger.Infof("Delete[repo]: Перемещение в корзину песни по ID: %d, версия: %d", id, expectedVersion)
+
+	query := `UPDATE songs SET deleted_at = NOW()
+              WHERE id = $1 AND deleted_at IS NULL AND ($2::int = 0 OR version = $2)`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
 
 	// Execute delete query and check how many rows were affected
-	result, err := r.db.GetPool().Exec(ctx, query, id)
+	result, err := r.db.GetPool().Exec(ctx, query, id, expectedVersion)
 	if err != nil {
 		r.logger.Errorf("Delete[repo]: Ошибка удаления песни по ID %d: %v", id, err)
 		return err
 	}
 
-	// If no rows affected, return ErrSongNotFound
+	// If no rows affected, song is either missing or has another version
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		r.logger.Warnf("Delete[repo]: Песня с ID %d не найдена для удаления", id)
-		return ErrSongNotFound
+		r.logger.Warnf("Delete[repo]: Песня с ID %d и версией %d не найдена для удаления", id, expectedVersion)
+		return r.missingOrConflict(ctx, id, expectedVersion)
 	}
 	r.logger.Infof("Delete[repo]: Успешно удалена песня по ID: %d", id)
 	return nil
 }
 
 // Create inserts new song into database and returns created song with generated ID, created_at, and updated_at fields
-func (r *Repo) Create(song models.Song) (models.Song, error) {
+func (r *Repo) Create(ctx context.Context, song models.Song) (models.Song, error) {
 	r.logger.Infof("Create[repo]: Создание новой песни: %+v", song)
 
-	query := `INSERT INTO songs ("group", song, release_date, text, link, created_at, updated_at) 
-              VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) RETURNING id, created_at, updated_at`
-	ctx := context.Background()
+	query := `WITH ` + upsertArtist + `
+              INSERT INTO songs (artist_id, "group", song, release_date, text, link, created_at, updated_at) 
+              SELECT artist.id, artist.name, $2, $3, $4, $5, NOW(), NOW() FROM artist
+              RETURNING ` + songColumns
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
 
-	// Execute query and scan returned ID, created_at, and updated_at into song object
-	err := r.db.GetPool().QueryRow(ctx, query, song.Group, song.Title, song.ReleaseDate, song.Text, song.Link).
-		Scan(&song.ID, &song.CreatedAt, &song.UpdatedAt)
+	// Execute query and scan returned song into song object
+	err := scanSong(r.db.GetPool().QueryRow(ctx, query, song.Group, song.Title, song.ReleaseDate, song.Text, song.Link), &song)
 	if err != nil {
 		r.logger.Errorf("Create[repo]: Ошибка создания песни: %+v, ошибка: %v", song, err)
 		return models.Song{}, err
@@ -198,3 +403,31 @@ func (r *Repo) Create(song models.Song) (models.Song, error) {
 	r.logger.Infof("Create[repo]: Успешно создана песня: %+v", song)
 	return song, nil
 }
+
+// missingOrConflict finds out why conditional modification of song affected no rows
+// It returns ErrVersionMismatch if song exists and version was expected, and ErrSongNotFound otherwise
+func (r *Repo) missingOrConflict(ctx context.Context, id int, expectedVersion int) error {
+	if expectedVersion == 0 {
+		return ErrSongNotFound
+	}
+
+	query := `SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.db.GetPool().QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		r.logger.Errorf("missingOrConflict[repo]: Ошибка проверки существования песни ID %d: %v", id, err)
+		return err
+	}
+	if !exists {
+		return ErrSongNotFound
+	}
+	return ErrVersionMismatch
+}
+
+// scanSong scans row selected with songColumns into song
+// Values of columns selected after songColumns are scanned into extra destinations
+func scanSong(row pgx.Row, song *models.Song, extra ...interface{}) error {
+	dest := []interface{}{&song.ID, &song.ArtistID, &song.Group, &song.Title, &song.ReleaseDate, &song.Text, &song.Link,
+		&song.EnrichmentStatus, &song.Version, &song.CreatedAt, &song.UpdatedAt, &song.DeletedAt}
+	return row.Scan(append(dest, extra...)...)
+}
@@ -0,0 +1,98 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// revisionColumns lists columns of song_revisions in order expected by scanRevision
+const revisionColumns = `song_id, revision, operation, artist_id, "group", song, release_date, text, link, enrichment_status, changed_at`
+
+// RevisionRepository defines methods for history of song changes
+// Revisions are written by trigger on songs table, so repository only reads them
+type RevisionRepository interface {
+	GetRevisions(ctx context.Context, songID, page, pageSize int) ([]models.SongRevision, error)
+	CountRevisions(ctx context.Context, songID int) (int64, error)
+	GetRevision(ctx context.Context, songID, revision int) (models.SongRevision, error)
+}
+
+// GetRevisions retrieves revisions of song, newest first, supporting pagination
+func (r *Repo) GetRevisions(ctx context.Context, songID, page, pageSize int) ([]models.SongRevision, error) {
+	r.logger.Infof("GetRevisions[repo]: Получение ревизий песни ID: %d, страница: %d, размер страницы: %d",
+		songID, page, pageSize)
+
+	query := `SELECT ` + revisionColumns + ` FROM song_revisions WHERE song_id = $1
+              ORDER BY revision DESC LIMIT $2 OFFSET $3`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, songID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		r.logger.Errorf("GetRevisions[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.SongRevision{}
+	for rows.Next() {
+		var revision models.SongRevision
+		if err = scanRevision(rows, &revision); err != nil {
+			r.logger.Errorf("GetRevisions[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetRevisions[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+
+	return revisions, nil
+}
+
+// CountRevisions returns number of revisions of song
+func (r *Repo) CountRevisions(ctx context.Context, songID int) (int64, error) {
+	query := `SELECT COUNT(*) FROM song_revisions WHERE song_id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var total int64
+	if err := r.db.GetPool().QueryRow(ctx, query, songID).Scan(&total); err != nil {
+		r.logger.Errorf("CountRevisions[repo]: Ошибка подсчета ревизий песни ID %d: %v", songID, err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetRevision retrieves single revision of song. If it doesn't exist, returns ErrRevisionNotFound
+func (r *Repo) GetRevision(ctx context.Context, songID, revision int) (models.SongRevision, error) {
+	r.logger.Infof("GetRevision[repo]: Получение ревизии %d песни ID: %d", revision, songID)
+
+	query := `SELECT ` + revisionColumns + ` FROM song_revisions WHERE song_id = $1 AND revision = $2`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var result models.SongRevision
+	if err := scanRevision(r.db.GetPool().QueryRow(ctx, query, songID, revision), &result); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("GetRevision[repo]: Ревизия %d песни ID %d не найдена", revision, songID)
+			return models.SongRevision{}, ErrRevisionNotFound
+		}
+		r.logger.Errorf("GetRevision[repo]: Ошибка получения ревизии %d песни ID %d: %v", revision, songID, err)
+		return models.SongRevision{}, err
+	}
+	return result, nil
+}
+
+// scanRevision scans row of revisionColumns into revision
+func scanRevision(row pgx.Row, revision *models.SongRevision) error {
+	return row.Scan(&revision.SongID, &revision.Revision, &revision.Operation, &revision.ArtistID, &revision.Group,
+		&revision.Title, &revision.ReleaseDate, &revision.Text, &revision.Link, &revision.EnrichmentStatus,
+		&revision.ChangedAt)
+}
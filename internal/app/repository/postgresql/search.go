@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"context"
+
+	"rest-songs/internal/app/models"
+)
+
+// headlineOptions configures ts_headline snippets: matched words are wrapped into <b></b>
+const headlineOptions = `StartSel=<b>, StopSel=</b>, MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "`
+
+// Search finds songs which title, group or text match full-text query and fit filter
+// Results are ordered by rank, and snippet of text with highlighted matches is built for each of them
+func (r *Repo) Search(ctx context.Context, search models.SongSearch, filter models.SongFilters, page, pageSize int) ([]models.SongSearchResult, error) {
+	r.logger.Infof("Search[repo]: Поиск песен по запросу: %+v, фильтр: %+v, страница: %d, размер страницы: %d",
+		search, filter, page, pageSize)
+
+	// Choose text search configurations to match against
+	var match, rank, headline string
+	switch search.Lang {
+	case models.SearchLangRussian:
+		match = `search_ru @@ q.ru`
+		rank = `ts_rank_cd(search_ru, q.ru)`
+		headline = `ts_headline('russian', found.text, q.ru, '` + headlineOptions + `')`
+	case models.SearchLangEnglish:
+		match = `search_en @@ q.en`
+		rank = `ts_rank_cd(search_en, q.en)`
+		headline = `ts_headline('english', found.text, q.en, '` + headlineOptions + `')`
+	default:
+		match = `(search_ru @@ q.ru OR search_en @@ q.en)`
+		rank = `GREATEST(ts_rank_cd(search_ru, q.ru), ts_rank_cd(search_en, q.en))`
+		headline = `CASE WHEN found.ru_match
+                         THEN ts_headline('russian', found.text, q.ru, '` + headlineOptions + `')
+                         ELSE ts_headline('english', found.text, q.en, '` + headlineOptions + `')
+                     END`
+	}
+
+	args := []interface{}{search.Query}
+	conditions, args := songFilterConditions(filter, args)
+
+	args = append(args, pageSize)
+	limit := placeholder(args)
+	args = append(args, (page-1)*pageSize)
+	offset := placeholder(args)
+
+	// Snippets are built in outer query only for rows of requested page, since ts_headline is expensive
+	query := `WITH q AS (
+                  SELECT websearch_to_tsquery('russian', $1) AS ru, websearch_to_tsquery('english', $1) AS en
+              )
+              SELECT ` + songColumns + `, rank::float8, ` + headline + `
+              FROM (
+                  SELECT ` + songColumns + `, search_ru @@ q.ru AS ru_match, ` + rank + ` AS rank
+                  FROM songs, q
+                  WHERE deleted_at IS NULL AND ` + match + conditions + `
+                  ORDER BY rank DESC, id
+                  LIMIT ` + limit + ` OFFSET ` + offset + `
+              ) found, q
+              ORDER BY rank DESC, id`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	r.logger.Debugf("Search[repo]: SQL запрос: %s, параметры: %+v", query, args)
+
+	rows, err := r.db.GetPool().Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Search[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.SongSearchResult{}
+	for rows.Next() {
+		var result models.SongSearchResult
+		if err = scanSong(rows, &result.Song, &result.Rank, &result.Snippet); err != nil {
+			r.logger.Errorf("Search[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("Search[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+
+	r.logger.Infof("Search[repo]: Найдено %d песен", len(results))
+	return results, nil
+}
@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+)
+
+// SyncedLyricsRepository defines methods for timings of song text lines
+type SyncedLyricsRepository interface {
+	GetLyricsTimings(ctx context.Context, songID int) ([]int, error)
+	SaveLyricsTimings(ctx context.Context, songID, version int, offsets []int) error
+}
+
+// GetLyricsTimings retrieves offsets of song text lines in milliseconds, ordered by line
+// Song without synced lyrics has no timings, so empty slice is returned for it
+func (r *Repo) GetLyricsTimings(ctx context.Context, songID int) ([]int, error) {
+	query := `SELECT offset_ms FROM song_lyrics_timings WHERE song_id = $1 ORDER BY line`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, songID)
+	if err != nil {
+		r.logger.Errorf("GetLyricsTimings[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	offsets := []int{}
+	for rows.Next() {
+		var offset int
+		if err = rows.Scan(&offset); err != nil {
+			r.logger.Errorf("GetLyricsTimings[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetLyricsTimings[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+	return offsets, nil
+}
+
+// SaveLyricsTimings replaces timings of song text lines, i-th offset belongs to line i+1
+// Timings are saved only if song still has given version, since they are validated against its text.
+// Otherwise ErrVersionMismatch is returned, or ErrSongNotFound if song doesn't exist
+func (r *Repo) SaveLyricsTimings(ctx context.Context, songID, version int, offsets []int) error {
+	r.logger.Infof("SaveLyricsTimings[repo]: Сохранение %d таймингов песни ID: %d, версия: %d", len(offsets), songID, version)
+
+	query := `WITH song AS (
+                  SELECT id FROM songs WHERE id = $1 AND version = $2 AND deleted_at IS NULL FOR UPDATE
+              ), cleared AS (
+                  DELETE FROM song_lyrics_timings WHERE song_id IN (SELECT id FROM song)
+              )
+              INSERT INTO song_lyrics_timings (song_id, line, offset_ms)
+              SELECT song.id, t.line, t.offset_ms
+              FROM song, unnest($3::int[]) WITH ORDINALITY AS t(offset_ms, line)
+              RETURNING song_id`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, songID, version, offsets)
+	if err != nil {
+		r.logger.Errorf("SaveLyricsTimings[repo]: Ошибка сохранения таймингов песни ID %d: %v", songID, err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return r.missingOrConflict(ctx, songID, version)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+// songSource returns table songs are selected from, appending its parameters to args
+// For zero asOf it is songs table itself. Otherwise it is built from revisions valid at asOf
+// under the same name and columns, so filters, sort keys and pagination apply to it unchanged.
+// Songs which were in trash at that moment are left out, including ones changed while in trash
+func songSource(asOf time.Time, args []interface{}) (string, []interface{}) {
+	if asOf.IsZero() {
+		return `songs`, args
+	}
+
+	args = append(args, asOf)
+	return `(SELECT r.song_id AS id, r.artist_id, r."group", r.song, r.release_date, r.text, r.link,
+                    r.enrichment_status, r.revision AS version, s.created_at, r.changed_at AS updated_at,
+                    NULL::timestamptz AS deleted_at
+             FROM song_revisions r JOIN songs s ON s.id = r.song_id
+             WHERE r.valid_period @> ` + placeholder(args) + `::timestamptz AND r.deleted_at IS NULL
+            ) AS songs`, args
+}
+
+// GetByIdAsOf retrieves song by ID as it was at given moment
+// If song didn't exist or was in trash at that moment, returns ErrSongNotFound
+func (r *Repo) GetByIdAsOf(ctx context.Context, id int, asOf time.Time) (models.Song, error) {
+	r.logger.Infof("GetByIdAsOf[repo]: Получение песни по ID: %d на момент: %s", id, asOf)
+
+	source, args := songSource(asOf, nil)
+	args = append(args, id)
+	query := `SELECT ` + songColumns + ` FROM ` + source + ` WHERE id = ` + placeholder(args)
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var song models.Song
+	if err := scanSong(r.db.GetPool().QueryRow(ctx, query, args...), &song); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("GetByIdAsOf[repo]: Песня с ID %d не найдена на момент %s", id, asOf)
+			return models.Song{}, ErrSongNotFound
+		}
+		r.logger.Errorf("GetByIdAsOf[repo]: Ошибка получения песни по ID %d: %v", id, err)
+		return models.Song{}, err
+	}
+	return song, nil
+}
@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"rest-songs/internal/app/models"
+)
+
+// TrashRepository defines methods for songs moved to trash by Delete
+type TrashRepository interface {
+	GetDeleted(ctx context.Context, page, pageSize int) ([]models.Song, error)
+	CountDeleted(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, id int) (models.Song, error)
+	Purge(ctx context.Context, olderThan time.Duration, limit int) (int64, error)
+}
+
+// GetDeleted retrieves songs in trash, most recently deleted first, supporting pagination
+func (r *Repo) GetDeleted(ctx context.Context, page, pageSize int) ([]models.Song, error) {
+	r.logger.Infof("GetDeleted[repo]: Получение песен из корзины, страница: %d, размер страницы: %d", page, pageSize)
+
+	query := `SELECT ` + songColumns + ` FROM songs WHERE deleted_at IS NOT NULL
+              ORDER BY deleted_at DESC, id DESC LIMIT $1 OFFSET $2`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.GetPool().Query(ctx, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		r.logger.Errorf("GetDeleted[repo]: Ошибка выполнения SQL запроса: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := []models.Song{}
+	for rows.Next() {
+		var song models.Song
+		if err = scanSong(rows, &song); err != nil {
+			r.logger.Errorf("GetDeleted[repo]: Ошибка сканирования строки: %v", err)
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+
+	if rows.Err() != nil {
+		r.logger.Errorf("GetDeleted[repo]: Ошибка при итерации по строкам: %v", rows.Err())
+		return nil, rows.Err()
+	}
+
+	r.logger.Infof("GetDeleted[repo]: Успешно получено %d песен", len(songs))
+	return songs, nil
+}
+
+// CountDeleted returns number of songs in trash
+func (r *Repo) CountDeleted(ctx context.Context) (int64, error) {
+	query := `SELECT COUNT(*) FROM songs WHERE deleted_at IS NOT NULL`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	var total int64
+	if err := r.db.GetPool().QueryRow(ctx, query).Scan(&total); err != nil {
+		r.logger.Errorf("CountDeleted[repo]: Ошибка подсчета песен в корзине: %v", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// Restore takes song out of trash by ID and returns restored song
+// Song takes current name of its artist, since artist could be renamed while song was in trash.
+// Song still waiting for details gets its enrichment job queued again, in case the job was finished meanwhile
+// If song with given ID is not in trash, returns ErrSongNotFound
+func (r *Repo) Restore(ctx context.Context, id int) (models.Song, error) {
+	r.logger.Infof("Restore[repo]: Восстановление песни из корзины по ID: %d", id)
+
+	query := `WITH restored AS (
+                  UPDATE songs SET deleted_at = NULL, "group" = (SELECT name FROM artists WHERE artists.id = songs.artist_id)
+                  WHERE id = $1 AND deleted_at IS NOT NULL
+                  RETURNING ` + songColumns + `
+              ), requeued AS (
+                  INSERT INTO enrichment_jobs (song_id) SELECT id FROM restored WHERE enrichment_status = 'pending'
+                  ON CONFLICT (song_id) DO UPDATE
+                  SET status = 'pending', attempts = 0, run_after = NOW(), locked_at = NULL, updated_at = NOW()
+                  WHERE enrichment_jobs.status IN ('done', 'dead')
+              )
+              SELECT ` + songColumns + ` FROM restored`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var song models.Song
+	if err := scanSong(r.db.GetPool().QueryRow(ctx, query, id), &song); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warnf("Restore[repo]: Песня с ID %d не найдена в корзине", id)
+			return models.Song{}, ErrSongNotFound
+		}
+		r.logger.Errorf("Restore[repo]: Ошибка восстановления песни по ID %d: %v", id, err)
+		return models.Song{}, err
+	}
+
+	r.logger.Infof("Restore[repo]: Успешно восстановлена песня: %+v", song)
+	return song, nil
+}
+
+// Purge permanently removes at most limit songs which are in trash longer than olderThan
+// It returns number of removed songs, so caller repeats it while whole batch is removed
+func (r *Repo) Purge(ctx context.Context, olderThan time.Duration, limit int) (int64, error) {
+	query := `DELETE FROM songs WHERE id IN (
+                  SELECT id FROM songs
+                  WHERE deleted_at < NOW() - $1 * INTERVAL '1 millisecond'
+                  ORDER BY deleted_at
+                  LIMIT $2
+              )`
+	ctx, cancel := withTimeout(ctx, r.timeouts.Bulk)
+	defer cancel()
+
+	result, err := r.db.GetPool().Exec(ctx, query, olderThan.Milliseconds(), limit)
+	if err != nil {
+		r.logger.Errorf("Purge[repo]: Ошибка очистки корзины: %v", err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,81 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"rest-songs/internal/app/repository/postgresql"
+)
+
+// purgeBatchSize limits number of songs removed by one statement, so purge doesn't hold locks for long
+const purgeBatchSize = 1000
+
+// Config holds settings for purging of trash
+// Zero Retention disables purge, so deleted songs are kept until restored
+type Config struct {
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// Purger permanently removes songs which stay in trash longer than retention period
+type Purger struct {
+	repo   postgresql.TrashRepository
+	cfg    Config
+	logger *logrus.Logger
+}
+
+// New creates new Purger instance and takes repository, Config and logger as parameters
+func New(repo postgresql.TrashRepository, cfg Config, logger *logrus.Logger) *Purger {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	return &Purger{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run purges trash at start and then every interval, blocking until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	if p.cfg.Retention <= 0 {
+		p.logger.Infof("Run[trash]: Очистка корзины отключена")
+		return
+	}
+	p.logger.Infof("Run[trash]: Запуск очистки корзины, срок хранения: %s, интервал: %s", p.cfg.Retention, p.cfg.Interval)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Infof("Run[trash]: Очистка корзины остановлена")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge removes expired songs batch by batch until there are none left
+func (p *Purger) purge(ctx context.Context) {
+	var total int64
+	for ctx.Err() == nil {
+		removed, err := p.repo.Purge(ctx, p.cfg.Retention, purgeBatchSize)
+		if err != nil {
+			p.logger.Errorf("purge[trash]: Ошибка очистки корзины: %v", err)
+			return
+		}
+		total += removed
+		if removed < purgeBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		p.logger.Infof("purge[trash]: Из корзины окончательно удалено %d песен", total)
+	}
+}
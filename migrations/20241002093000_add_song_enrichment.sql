@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs
+    ALTER COLUMN release_date DROP NOT NULL,
+    ALTER COLUMN text SET DEFAULT '',
+    ALTER COLUMN link SET DEFAULT '',
+    ADD COLUMN enrichment_status TEXT NOT NULL DEFAULT 'done'
+        CHECK (enrichment_status IN ('pending', 'done', 'failed'));
+
+CREATE TABLE enrichment_jobs (
+                       id SERIAL PRIMARY KEY,
+                       song_id INT NOT NULL UNIQUE REFERENCES songs(id) ON DELETE CASCADE,
+                       status TEXT NOT NULL DEFAULT 'pending'
+                           CHECK (status IN ('pending', 'running', 'done', 'dead')),
+                       attempts INT NOT NULL DEFAULT 0,
+                       last_error TEXT,
+                       run_after TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                       locked_at TIMESTAMPTZ,
+                       created_at TIMESTAMPTZ DEFAULT NOW(),
+                       updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_enrichment_jobs_queue ON enrichment_jobs(run_after) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE enrichment_jobs;
+
+DELETE FROM songs WHERE release_date IS NULL;
+
+ALTER TABLE songs
+    DROP COLUMN enrichment_status,
+    ALTER COLUMN release_date SET NOT NULL,
+    ALTER COLUMN text DROP DEFAULT,
+    ALTER COLUMN link DROP DEFAULT;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE artists (
+                       id SERIAL PRIMARY KEY,
+                       name TEXT NOT NULL,
+                       created_at TIMESTAMPTZ DEFAULT NOW(),
+                       updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Names differing only in case or surrounding spaces belong to the same artist
+CREATE UNIQUE INDEX idx_artists_name ON artists(lower(name));
+
+INSERT INTO artists (name)
+SELECT DISTINCT ON (lower(btrim("group"))) btrim("group")
+FROM songs
+ORDER BY lower(btrim("group")), btrim("group");
+
+ALTER TABLE songs ADD COLUMN artist_id INT REFERENCES artists(id) ON DELETE RESTRICT;
+
+-- songs."group" is kept as a copy of artists.name, so it is synced with canonical spelling
+UPDATE songs s SET artist_id = a.id, "group" = a.name
+FROM artists a
+WHERE lower(a.name) = lower(btrim(s."group"));
+
+ALTER TABLE songs ALTER COLUMN artist_id SET NOT NULL;
+
+CREATE INDEX idx_songs_artist_id ON songs(artist_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN artist_id;
+DROP TABLE artists;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs
+    ADD COLUMN search_ru tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(song, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce("group", '')), 'B') ||
+        setweight(to_tsvector('russian', coalesce(text, '')), 'C')
+    ) STORED,
+    ADD COLUMN search_en tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(song, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce("group", '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(text, '')), 'C')
+    ) STORED;
+
+CREATE INDEX idx_songs_search_ru ON songs USING GIN (search_ru);
+CREATE INDEX idx_songs_search_en ON songs USING GIN (search_en);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN search_ru, DROP COLUMN search_en;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes serve case-insensitive exact, prefix and substring (ILIKE) filters
+CREATE INDEX idx_songs_group_trgm ON songs USING GIN ("group" gin_trgm_ops);
+CREATE INDEX idx_songs_song_trgm ON songs USING GIN (song gin_trgm_ops);
+CREATE INDEX idx_songs_created_at ON songs(created_at);
+CREATE INDEX idx_songs_updated_at ON songs(updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_songs_updated_at;
+DROP INDEX idx_songs_created_at;
+DROP INDEX idx_songs_song_trgm;
+DROP INDEX idx_songs_group_trgm;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- Version is incremented by every update of song, whichever query performs it
+CREATE FUNCTION songs_bump_version() RETURNS trigger AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_bump_version
+    BEFORE UPDATE ON songs
+    FOR EACH ROW EXECUTE FUNCTION songs_bump_version();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER songs_bump_version ON songs;
+DROP FUNCTION songs_bump_version();
+ALTER TABLE songs DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Trash is small compared to library, so only deleted songs are indexed
+CREATE INDEX idx_songs_deleted_at ON songs(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE songs DROP COLUMN deleted_at;
+-- +goose StatementEnd
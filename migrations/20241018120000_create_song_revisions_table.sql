@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_revisions (
+                       song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+                       revision INT NOT NULL,
+                       operation TEXT NOT NULL CHECK (operation IN ('create', 'update', 'delete', 'restore')),
+                       artist_id INT NOT NULL,
+                       "group" TEXT NOT NULL,
+                       song TEXT NOT NULL,
+                       release_date TIMESTAMPTZ,
+                       text TEXT NOT NULL,
+                       link TEXT NOT NULL,
+                       enrichment_status TEXT NOT NULL,
+                       changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                       PRIMARY KEY (song_id, revision)
+);
+
+-- Snapshot of song is stored by trigger, so it is written in the same transaction as change itself
+-- Revision number is version of song after change
+CREATE FUNCTION songs_store_revision() RETURNS trigger AS $$
+DECLARE
+    op TEXT := 'update';
+BEGIN
+    -- Any change of song in trash, not only moving it there, keeps it deleted
+    IF TG_OP = 'INSERT' THEN
+        op := 'create';
+    ELSIF NEW.deleted_at IS NOT NULL THEN
+        op := 'delete';
+    ELSIF OLD.deleted_at IS NOT NULL THEN
+        op := 'restore';
+    END IF;
+
+    INSERT INTO song_revisions (song_id, revision, operation, artist_id, "group", song, release_date, text, link,
+                                enrichment_status)
+    VALUES (NEW.id, NEW.version, op, NEW.artist_id, NEW."group", NEW.song, NEW.release_date, NEW.text, NEW.link,
+            NEW.enrichment_status);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_store_revision
+    AFTER INSERT OR UPDATE ON songs
+    FOR EACH ROW EXECUTE FUNCTION songs_store_revision();
+
+-- Current state of existing songs becomes their first known revision
+INSERT INTO song_revisions (song_id, revision, operation, artist_id, "group", song, release_date, text, link,
+                            enrichment_status, changed_at)
+SELECT id, version, CASE WHEN deleted_at IS NOT NULL THEN 'delete' WHEN version = 1 THEN 'create' ELSE 'update' END,
+       artist_id, "group", song, release_date, text, link, enrichment_status, COALESCE(updated_at, NOW())
+FROM songs;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER songs_store_revision ON songs;
+DROP FUNCTION songs_store_revision();
+DROP TABLE song_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Revision is valid from its change until the next change of song, current revision has open upper bound
+-- deleted_at tells whether song was in trash while revision was valid
+ALTER TABLE song_revisions
+    ADD COLUMN valid_to TIMESTAMPTZ,
+    ADD COLUMN valid_period TSTZRANGE GENERATED ALWAYS AS (tstzrange(changed_at, valid_to)) STORED,
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+UPDATE song_revisions r SET valid_to = next.changed_at
+FROM song_revisions next
+WHERE next.song_id = r.song_id AND next.revision = r.revision + 1;
+
+UPDATE song_revisions r SET deleted_at = s.deleted_at
+FROM songs s
+WHERE s.id = r.song_id AND r.operation = 'delete' AND s.deleted_at IS NOT NULL AND r.changed_at >= s.deleted_at;
+
+UPDATE song_revisions SET deleted_at = changed_at WHERE operation = 'delete' AND deleted_at IS NULL;
+
+CREATE INDEX idx_song_revisions_valid_period ON song_revisions USING GIST (valid_period);
+
+CREATE OR REPLACE FUNCTION songs_store_revision() RETURNS trigger AS $$
+DECLARE
+    op TEXT := 'update';
+BEGIN
+    -- Any change of song in trash, not only moving it there, keeps it deleted
+    IF TG_OP = 'INSERT' THEN
+        op := 'create';
+    ELSIF NEW.deleted_at IS NOT NULL THEN
+        op := 'delete';
+    ELSIF OLD.deleted_at IS NOT NULL THEN
+        op := 'restore';
+    END IF;
+
+    UPDATE song_revisions SET valid_to = NOW() WHERE song_id = NEW.id AND valid_to IS NULL;
+
+    INSERT INTO song_revisions (song_id, revision, operation, artist_id, "group", song, release_date, text, link,
+                                enrichment_status, deleted_at)
+    VALUES (NEW.id, NEW.version, op, NEW.artist_id, NEW."group", NEW.song, NEW.release_date, NEW.text, NEW.link,
+            NEW.enrichment_status, NEW.deleted_at);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION songs_store_revision() RETURNS trigger AS $$
+DECLARE
+    op TEXT := 'update';
+BEGIN
+    -- Any change of song in trash, not only moving it there, keeps it deleted
+    IF TG_OP = 'INSERT' THEN
+        op := 'create';
+    ELSIF NEW.deleted_at IS NOT NULL THEN
+        op := 'delete';
+    ELSIF OLD.deleted_at IS NOT NULL THEN
+        op := 'restore';
+    END IF;
+
+    INSERT INTO song_revisions (song_id, revision, operation, artist_id, "group", song, release_date, text, link,
+                                enrichment_status)
+    VALUES (NEW.id, NEW.version, op, NEW.artist_id, NEW."group", NEW.song, NEW.release_date, NEW.text, NEW.link,
+            NEW.enrichment_status);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE song_revisions DROP COLUMN deleted_at, DROP COLUMN valid_period, DROP COLUMN valid_to;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_lyrics_timings (
+                       song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+                       line INT NOT NULL CHECK (line > 0),
+                       offset_ms INT NOT NULL CHECK (offset_ms >= 0),
+                       PRIMARY KEY (song_id, line)
+);
+
+-- Timings refer to lines of song text, so they are dropped when text changes
+CREATE FUNCTION songs_clear_lyrics_timings() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM song_lyrics_timings WHERE song_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_clear_lyrics_timings
+    AFTER UPDATE OF text ON songs
+    FOR EACH ROW WHEN (OLD.text IS DISTINCT FROM NEW.text)
+    EXECUTE FUNCTION songs_clear_lyrics_timings();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER songs_clear_lyrics_timings ON songs;
+DROP FUNCTION songs_clear_lyrics_timings();
+DROP TABLE song_lyrics_timings;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_chord_sheets (
+                       song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+                       document TEXT NOT NULL,
+                       created_at TIMESTAMPTZ DEFAULT NOW(),
+                       updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_chord_sheets;
+-- +goose StatementEnd
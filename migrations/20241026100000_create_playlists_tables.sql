@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE playlists (
+                       id SERIAL PRIMARY KEY,
+                       name TEXT NOT NULL,
+                       description TEXT NOT NULL DEFAULT '',
+                       created_at TIMESTAMPTZ DEFAULT NOW(),
+                       updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Items are ordered by rank. Ranks are spaced apart, so item is moved by giving it rank
+-- between its new neighbours, and whole playlist is renumbered only when there is no gap left.
+-- Uniqueness is checked at commit, since renumbering shifts ranks one by one
+CREATE TABLE playlist_items (
+                       id SERIAL PRIMARY KEY,
+                       playlist_id INT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+                       song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+                       rank BIGINT NOT NULL,
+                       added_at TIMESTAMPTZ DEFAULT NOW(),
+                       CONSTRAINT playlist_items_rank_key UNIQUE (playlist_id, rank) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX playlist_items_song_id_idx ON playlist_items (song_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE playlist_items;
+DROP TABLE playlists;
+-- +goose StatementEnd
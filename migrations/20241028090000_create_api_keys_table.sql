@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_keys (
+                       id SERIAL PRIMARY KEY,
+                       name TEXT NOT NULL,
+                       prefix TEXT NOT NULL,
+                       key_hash BYTEA NOT NULL UNIQUE,
+                       role TEXT NOT NULL CHECK (role IN ('reader', 'editor', 'admin')),
+                       created_at TIMESTAMPTZ DEFAULT NOW(),
+                       last_used_at TIMESTAMPTZ,
+                       revoked_at TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE rate_limit_quotas (
+                       client TEXT NOT NULL,
+                       class TEXT NOT NULL,
+                       day DATE NOT NULL,
+                       used BIGINT NOT NULL DEFAULT 0,
+                       PRIMARY KEY (client, class, day)
+);
+
+CREATE INDEX rate_limit_quotas_day_idx ON rate_limit_quotas (day);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE rate_limit_quotas;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+// Package migrations embeds goose SQL migrations, so the application knows which schema version it expects
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// LatestVersion returns version of the newest migration, taken from numeric prefix of its file name
+func LatestVersion() (int64, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has no version prefix", name)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}